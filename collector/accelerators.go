@@ -0,0 +1,248 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/DazWilkin/gcp-exporter/gcp"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"google.golang.org/api/cloudresourcemanager/v1"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/container/v1"
+)
+
+var (
+	_ prometheus.Collector = (*AcceleratorsCollector)(nil)
+)
+
+// AcceleratorsCollector represents GPUs (and other accelerators) attached to Compute Engine instances and GKE nodes
+type AcceleratorsCollector struct {
+	account          *gcp.Account
+	computeService   *compute.Service
+	containerService *container.Service
+
+	Accelerators  *prometheus.Desc
+	QuotaHeadroom *prometheus.Desc
+}
+
+// NewAcceleratorsCollector returns a new AcceleratorsCollector
+func NewAcceleratorsCollector(account *gcp.Account) (*AcceleratorsCollector, error) {
+	subsystem := "accelerators"
+
+	ctx := context.Background()
+	computeService, err := compute.NewService(ctx)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	containerService, err := container.NewService(ctx)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return &AcceleratorsCollector{
+		account:          account,
+		computeService:   computeService,
+		containerService: containerService,
+
+		Accelerators: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "", "accelerators"),
+			"Number of accelerators (e.g. GPUs) attached to running Compute Engine instances (source is compute) and GKE nodes (source is gke)",
+			withResourceLabels(account,
+				"project",
+				"location",
+				"accelerator_type",
+				"source",
+			),
+			nil,
+		),
+		QuotaHeadroom: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "quota_headroom"),
+			"Number of accelerators (e.g. GPUs) that may be added before reaching the quota limit (region is global for project-wide quotas)",
+			[]string{
+				"project",
+				"region",
+				"metric",
+			},
+			nil,
+		),
+	}, nil
+}
+
+// Collect implements Prometheus' Collector interface and is used to collect metrics
+func (c *AcceleratorsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx := context.Background()
+
+	// Enumerate all of the projects
+	// WaitGroup is used for project Accelerators|Quotas only (not the projects themselves)
+	var wg sync.WaitGroup
+	for _, p := range c.account.Projects {
+		log.Printf("[AcceleratorsCollector] Project: %s", p.ProjectId)
+
+		wg.Add(1)
+		go func(p *cloudresourcemanager.Project) {
+			defer wg.Done()
+			c.collectAccelerators(ctx, ch, p)
+		}(p)
+
+		wg.Add(1)
+		go func(p *cloudresourcemanager.Project) {
+			defer wg.Done()
+			c.collectQuotas(ctx, ch, p)
+		}(p)
+	}
+	wg.Wait()
+}
+
+// collectAccelerators collects the accelerators attached to the project's instances and GKE nodes
+// GKE nodes are Compute Engine instances (labeled goog-gke-node) and are attributed to GKE (not Compute Engine)
+// GKE nodes' accelerators are those of their node pool's config
+func (c *AcceleratorsCollector) collectAccelerators(ctx context.Context, ch chan<- prometheus.Metric, p *cloudresourcemanager.Project) {
+	nodePools := c.listNodePoolAccelerators(ctx, p)
+
+	// Accelerators are aggregated by location (zone), accelerator type, source and allowed resource labels
+	counts := map[string]int64{}
+
+	rqst := c.computeService.Instances.AggregatedList(p.ProjectId).MaxResults(500).ReturnPartialSuccess(true)
+	if err := rqst.Pages(ctx, func(page *compute.InstanceAggregatedList) error {
+		for scope, scoped := range page.Items {
+			zone := strings.TrimPrefix(scope, "zones/")
+			for _, instance := range scoped.Instances {
+				// Accelerators of stopped (TERMINATED) and suspended instances are not in use (nor counted by quotas' usage)
+				if instance.Status != "RUNNING" {
+					continue
+				}
+				resourceLabels := c.account.Labels.Values(instance.Labels)
+				if _, ok := instance.Labels["goog-gke-node"]; ok {
+					if accelerators, ok := nodePoolAccelerators(nodePools, zone, instance.Labels); ok {
+						for _, accelerator := range accelerators {
+							counts[groupKey(append([]string{zone, accelerator.AcceleratorType, "gke"}, resourceLabels...)...)] += accelerator.AcceleratorCount
+						}
+						continue
+					}
+					// Node pools that could not be listed fallback to the node's accelerators
+					for _, accelerator := range instance.GuestAccelerators {
+						counts[groupKey(append([]string{zone, path.Base(accelerator.AcceleratorType), "gke"}, resourceLabels...)...)] += accelerator.AcceleratorCount
+					}
+					continue
+				}
+
+				for _, accelerator := range instance.GuestAccelerators {
+					counts[groupKey(append([]string{zone, path.Base(accelerator.AcceleratorType), "compute"}, resourceLabels...)...)] += accelerator.AcceleratorCount
+				}
+			}
+		}
+		return nil
+	}); err != nil {
+		logError("AcceleratorsCollector", p.ProjectId, err)
+		return
+	}
+
+	for k, count := range counts {
+		ch <- prometheus.MustNewConstMetric(
+			c.Accelerators,
+			prometheus.GaugeValue,
+			float64(count),
+			append([]string{p.ProjectId}, groupValues(k)...)...,
+		)
+	}
+}
+
+// listNodePoolAccelerators returns the accelerators of the project's GKE node pools' configs
+// Node pools are keyed by cluster and node pool name
+func (c *AcceleratorsCollector) listNodePoolAccelerators(ctx context.Context, p *cloudresourcemanager.Project) map[string][]*container.AcceleratorConfig {
+	nodePools := map[string][]*container.AcceleratorConfig{}
+
+	parent := fmt.Sprintf("projects/%s/locations/-", p.ProjectId)
+	resp, err := c.containerService.Projects.Locations.Clusters.List(parent).Context(ctx).Do()
+	if err != nil {
+		logError("AcceleratorsCollector", p.ProjectId, err)
+		return nodePools
+	}
+
+	for _, cluster := range resp.Clusters {
+		for _, nodePool := range cluster.NodePools {
+			if nodePool.Config == nil {
+				continue
+			}
+			nodePools[nodePoolKey(cluster.Location, cluster.Name, nodePool.Name)] = nodePool.Config.Accelerators
+		}
+	}
+
+	return nodePools
+}
+
+// nodePoolKey returns the key of a GKE cluster's node pool
+// Clusters' names are unique only within their location (zone or region)
+func nodePoolKey(location, cluster, nodePool string) string {
+	return location + "/" + cluster + "/" + nodePool
+}
+
+// nodePoolAccelerators returns the accelerators of a GKE node's node pool
+// The node's cluster's location is its goog-k8s-cluster-location label or,
+// for nodes without the label, the node's zone (zonal clusters) or region (regional clusters)
+func nodePoolAccelerators(nodePools map[string][]*container.AcceleratorConfig, zone string, labels map[string]string) ([]*container.AcceleratorConfig, bool) {
+	cluster := labels["goog-k8s-cluster-name"]
+	nodePool := labels["goog-k8s-node-pool-name"]
+
+	locations := []string{zone, regionOf(zone)}
+	if location, ok := labels["goog-k8s-cluster-location"]; ok {
+		locations = []string{location}
+	}
+	for _, location := range locations {
+		if accelerators, ok := nodePools[nodePoolKey(location, cluster, nodePool)]; ok {
+			return accelerators, true
+		}
+	}
+	return nil, false
+}
+
+// collectQuotas collects the headroom of the project's regional and project-wide GPU quotas
+// GPU quota metrics include GPUS_ALL_REGIONS, NVIDIA_T4_GPUS and PREEMPTIBLE_NVIDIA_T4_GPUS
+func (c *AcceleratorsCollector) collectQuotas(ctx context.Context, ch chan<- prometheus.Metric, p *cloudresourcemanager.Project) {
+	collect := func(region string, quotas []*compute.Quota) {
+		for _, quota := range quotas {
+			if !strings.Contains(quota.Metric, "GPUS") {
+				continue
+			}
+			ch <- prometheus.MustNewConstMetric(
+				c.QuotaHeadroom,
+				prometheus.GaugeValue,
+				quota.Limit-quota.Usage,
+				[]string{
+					p.ProjectId,
+					region,
+					quota.Metric,
+				}...,
+			)
+		}
+	}
+
+	regionList, err := c.computeService.Regions.List(p.ProjectId).Context(ctx).Do()
+	if err != nil {
+		logError("AcceleratorsCollector", p.ProjectId, err)
+		return
+	}
+	for _, r := range regionList.Items {
+		collect(r.Name, r.Quotas)
+	}
+
+	project, err := c.computeService.Projects.Get(p.ProjectId).Context(ctx).Do()
+	if err != nil {
+		logError("AcceleratorsCollector", p.ProjectId, err)
+		return
+	}
+	collect("global", project.Quotas)
+}
+
+// Describe implements Prometheus' Collector interface and is used to describe metrics
+func (c *AcceleratorsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.Accelerators
+	ch <- c.QuotaHeadroom
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"path"
 	"sync"
 
 	"github.com/DazWilkin/gcp-exporter/gcp"
@@ -48,27 +49,27 @@ func NewArtifactRegistryCollector(account *gcp.Account) (*ArtifactRegistryCollec
 		Registries: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, subsystem, "registries"),
 			"Number of Registries",
-			[]string{
+			withResourceLabels(account,
 				"project",
-			},
+			),
 			nil,
 		),
 		Locations: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, subsystem, "locations"),
 			"Number of Locations",
-			[]string{
+			withResourceLabels(account,
 				"project",
 				"location",
-			},
+			),
 			nil,
 		),
 		Formats: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, subsystem, "formats"),
 			"Number of Formats",
-			[]string{
+			withResourceLabels(account,
 				"project",
 				"format",
-			},
+			),
 			nil,
 		),
 	}, nil
@@ -76,6 +77,8 @@ func NewArtifactRegistryCollector(account *gcp.Account) (*ArtifactRegistryCollec
 
 // Collect implements Prometheus' Collector interface and is used to collect metrics
 func (c *ArtifactRegistryCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx := context.Background()
+
 	// Enumerate all of the projects
 	var wg sync.WaitGroup
 	for _, p := range c.account.Projects {
@@ -84,9 +87,15 @@ func (c *ArtifactRegistryCollector) Collect(ch chan<- prometheus.Metric) {
 			defer wg.Done()
 			log.Printf("[ArtifactRegistryCollector] Project: %s", p.ProjectId)
 			name := fmt.Sprintf("projects/%s", p.ProjectId)
+			// Locations are paginated; repositories in unlisted locations would otherwise be recorded as deleted
+			locationIDs := []string{}
 			rqst := c.artifactregistryService.Projects.Locations.List(name)
-			resp, err := rqst.Do()
-			if err != nil {
+			if err := rqst.Pages(ctx, func(page *artifactregistry.ListLocationsResponse) error {
+				for _, l := range page.Locations {
+					locationIDs = append(locationIDs, l.LocationId)
+				}
+				return nil
+			}); err != nil {
 				if e, ok := err.(*googleapi.Error); ok {
 					if e.Code == http.StatusForbidden {
 						// Probably (!) Artifact Registry API has not been enabled for Project (p)
@@ -101,15 +110,20 @@ func (c *ArtifactRegistryCollector) Collect(ch chan<- prometheus.Metric) {
 				return
 			}
 
-			repositories := 0
+			// Counts are grouped by the allowed resource labels' values
+			// Projects without repositories report 0 (with placeholder values)
+			repositories := map[string]int{
+				groupKey(append([]string{p.ProjectId}, c.account.Labels.Values(nil)...)...): 0,
+			}
 			locations := make(map[string]int)
 			formats := make(map[string]int)
+			resources := []gcp.Resource{}
 
 			// For each Location
 			// Enumerate the list of repositories
-			for _, l := range resp.Locations {
+			for _, locationID := range locationIDs {
 				// LocationID is the short form e.g. "us-west1"
-				parent := fmt.Sprintf("projects/%s/locations/%s", p.ProjectId, l.LocationId)
+				parent := fmt.Sprintf("projects/%s/locations/%s", p.ProjectId, locationID)
 				rqst := c.artifactregistryService.Projects.Locations.Repositories.List(parent)
 
 				for {
@@ -126,14 +140,21 @@ func (c *ArtifactRegistryCollector) Collect(ch chan<- prometheus.Metric) {
 						return
 					}
 
-					// If there are any repositories in this location
-					if len(resp.Repositories) > 0 {
-						repositories += len(resp.Repositories)
-						locations[l.LocationId]++
-
-						for _, repository := range resp.Repositories {
-							formats[repository.Format]++
-						}
+					// Locations are counted once (for each allowed resource labels' values) if there are any repositories in the location
+					for _, repository := range resp.Repositories {
+						resourceLabels := c.account.Labels.Values(repository.Labels)
+						repositories[groupKey(append([]string{p.ProjectId}, resourceLabels...)...)]++
+						locations[groupKey(append([]string{p.ProjectId, locationID}, resourceLabels...)...)] = 1
+						formats[groupKey(append([]string{p.ProjectId, repository.Format}, resourceLabels...)...)]++
+
+						resources = append(resources, gcp.Resource{
+							Project:  p.ProjectId,
+							Service:  "artifact_registry",
+							Type:     "repository",
+							Location: locationID,
+							Name:     path.Base(repository.Name),
+							Labels:   repository.Labels,
+						})
 					}
 
 					// If there are no more pages, we're done
@@ -146,35 +167,33 @@ func (c *ArtifactRegistryCollector) Collect(ch chan<- prometheus.Metric) {
 				}
 			}
 
-			ch <- prometheus.MustNewConstMetric(
-				c.Registries,
-				prometheus.GaugeValue,
-				float64(repositories),
-				[]string{
-					p.ProjectId,
-				}...,
-			)
+			c.account.Inventory.Update(p.ProjectId, "artifact_registry", "repository", resources)
 
-			for location, count := range locations {
+			for k, count := range repositories {
+				if count == 0 && len(resources) != 0 {
+					continue
+				}
+				ch <- prometheus.MustNewConstMetric(
+					c.Registries,
+					prometheus.GaugeValue,
+					float64(count),
+					groupValues(k)...,
+				)
+			}
+			for k, count := range locations {
 				ch <- prometheus.MustNewConstMetric(
 					c.Locations,
 					prometheus.GaugeValue,
 					float64(count),
-					[]string{
-						p.ProjectId,
-						location,
-					}...,
+					groupValues(k)...,
 				)
 			}
-			for format, count := range formats {
+			for k, count := range formats {
 				ch <- prometheus.MustNewConstMetric(
 					c.Formats,
 					prometheus.GaugeValue,
 					float64(count),
-					[]string{
-						p.ProjectId,
-						format,
-					}...,
+					groupValues(k)...,
 				)
 			}
 		}(p)
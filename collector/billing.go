@@ -0,0 +1,206 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/DazWilkin/gcp-exporter/gcp"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"google.golang.org/api/billingbudgets/v1"
+	"google.golang.org/api/cloudbilling/v1"
+	"google.golang.org/api/cloudresourcemanager/v1"
+)
+
+var (
+	_ prometheus.Collector = (*BillingCollector)(nil)
+)
+
+// BillingCollector represents Cloud Billing
+type BillingCollector struct {
+	account               *gcp.Account
+	cloudbillingService   *cloudbilling.APIService
+	billingbudgetsService *billingbudgets.Service
+
+	Enabled              *prometheus.Desc
+	BudgetAmount         *prometheus.Desc
+	BudgetThresholdRatio *prometheus.Desc
+}
+
+// NewBillingCollector returns a new BillingCollector
+func NewBillingCollector(account *gcp.Account) (*BillingCollector, error) {
+	subsystem := "billing"
+
+	ctx := context.Background()
+	cloudbillingService, err := cloudbilling.NewService(ctx)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	billingbudgetsService, err := billingbudgets.NewService(ctx)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return &BillingCollector{
+		account:               account,
+		cloudbillingService:   cloudbillingService,
+		billingbudgetsService: billingbudgetsService,
+
+		Enabled: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "enabled"),
+			"1 if billing is enabled for the project, 0 otherwise",
+			[]string{
+				"project",
+				"billing_account",
+			},
+			nil,
+		),
+		BudgetAmount: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "budget_amount"),
+			"Specified amount of the budget",
+			[]string{
+				"billing_account",
+				"budget",
+				"display_name",
+				"currency",
+			},
+			nil,
+		),
+		BudgetThresholdRatio: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "budget_threshold_ratio"),
+			"Ratio of the budget's amount at which the threshold rule is triggered",
+			[]string{
+				"billing_account",
+				"budget",
+				"display_name",
+				"basis",
+				"rule",
+			},
+			nil,
+		),
+	}, nil
+}
+
+// Collect implements Prometheus' Collector interface and is used to collect metrics
+func (c *BillingCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx := context.Background()
+
+	// Billing accounts linked to the projects
+	// Budgets belong to billing accounts (not projects)
+	var mu sync.Mutex
+	billingAccounts := map[string]bool{}
+
+	// Enumerate all of the projects
+	var wg sync.WaitGroup
+	for _, p := range c.account.Projects {
+		wg.Add(1)
+		go func(p *cloudresourcemanager.Project) {
+			defer wg.Done()
+			log.Printf("[BillingCollector] Project: %s", p.ProjectId)
+
+			name := fmt.Sprintf("projects/%s", p.ProjectId)
+			info, err := c.cloudbillingService.Projects.GetBillingInfo(name).Context(ctx).Do()
+			if err != nil {
+				logError("BillingCollector", p.ProjectId, err)
+				return
+			}
+
+			// BillingAccountName == billingAccounts/{billing_account}
+			billingAccount := strings.TrimPrefix(info.BillingAccountName, "billingAccounts/")
+			if billingAccount != "" {
+				mu.Lock()
+				billingAccounts[billingAccount] = true
+				mu.Unlock()
+			}
+
+			ch <- prometheus.MustNewConstMetric(
+				c.Enabled,
+				prometheus.GaugeValue,
+				func(enabled bool) float64 {
+					if enabled {
+						return 1.0
+					}
+					return 0.0
+				}(info.BillingEnabled),
+				[]string{
+					p.ProjectId,
+					billingAccount,
+				}...,
+			)
+		}(p)
+	}
+	wg.Wait()
+
+	// Enumerate the billing accounts' budgets
+	for billingAccount := range billingAccounts {
+		wg.Add(1)
+		go func(billingAccount string) {
+			defer wg.Done()
+			log.Printf("[BillingCollector] Billing Account: %s", billingAccount)
+
+			parent := fmt.Sprintf("billingAccounts/%s", billingAccount)
+			rqst := c.billingbudgetsService.BillingAccounts.Budgets.List(parent)
+			if err := rqst.Pages(ctx, func(page *billingbudgets.GoogleCloudBillingBudgetsV1ListBudgetsResponse) error {
+				for _, budget := range page.Budgets {
+					c.collectBudget(ch, billingAccount, budget)
+				}
+				return nil
+			}); err != nil {
+				logError("BillingCollector", billingAccount, err)
+			}
+		}(billingAccount)
+	}
+	wg.Wait()
+}
+
+// collectBudget collects a budget's metrics
+func (c *BillingCollector) collectBudget(ch chan<- prometheus.Metric, billingAccount string, budget *billingbudgets.GoogleCloudBillingBudgetsV1Budget) {
+	// Budgets' display names are neither unique nor required so budgets are identified by their ID
+	// Name == billingAccounts/{billing_account}/budgets/{budget}
+	id := path.Base(budget.Name)
+
+	// Budgets that use the last period's amount do not have a specified amount
+	if budget.Amount != nil && budget.Amount.SpecifiedAmount != nil {
+		amount := budget.Amount.SpecifiedAmount
+		ch <- prometheus.MustNewConstMetric(
+			c.BudgetAmount,
+			prometheus.GaugeValue,
+			float64(amount.Units)+float64(amount.Nanos)/1e9,
+			[]string{
+				billingAccount,
+				id,
+				budget.DisplayName,
+				amount.CurrencyCode,
+			}...,
+		)
+	}
+
+	for i, rule := range budget.ThresholdRules {
+		ch <- prometheus.MustNewConstMetric(
+			c.BudgetThresholdRatio,
+			prometheus.GaugeValue,
+			rule.ThresholdPercent,
+			[]string{
+				billingAccount,
+				id,
+				budget.DisplayName,
+				rule.SpendBasis,
+				strconv.Itoa(i),
+			}...,
+		)
+	}
+}
+
+// Describe implements Prometheus' Collector interface and is used to describe metrics
+func (c *BillingCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.Enabled
+	ch <- c.BudgetAmount
+	ch <- c.BudgetThresholdRatio
+}
@@ -2,34 +2,65 @@ package collector
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
-	"net/http"
+	"path"
 	"sync"
+	"time"
 
 	"github.com/DazWilkin/gcp-exporter/gcp"
 	"github.com/prometheus/client_golang/prometheus"
 
 	"google.golang.org/api/cloudresourcemanager/v1"
-	"google.golang.org/api/googleapi"
-	"google.golang.org/api/run/v1"
+	"google.golang.org/api/run/v2"
 )
 
 var (
 	_ prometheus.Collector = (*CloudRunCollector)(nil)
 )
 
+var (
+	// errLookbackExceeded stops paging through a job's executions once they were created before the lookback window
+	errLookbackExceeded = errors.New("execution was created before the lookback window")
+)
+
+var (
+	// invokerRoles are the IAM roles that permit invoking Cloud Run services
+	invokerRoles = map[string]bool{
+		"roles/run.invoker":         true,
+		"roles/run.servicesInvoker": true,
+	}
+)
+
 // CloudRunCollector represents Cloud Run
 type CloudRunCollector struct {
 	account         *gcp.Account
-	cloudrunService *run.APIService
+	cloudrunService *run.Service
+	// lookback is the window within which job executions are enumerated
+	lookback time.Duration
+
+	Jobs                         *prometheus.Desc
+	Services                     *prometheus.Desc
+	ServiceInfo                  *prometheus.Desc
+	ServiceReady                 *prometheus.Desc
+	ServiceMinInstances          *prometheus.Desc
+	ServiceMaxInstances          *prometheus.Desc
+	ServiceLastDeployedTimestamp *prometheus.Desc
+	ServicePublic                *prometheus.Desc
 
-	Jobs     *prometheus.Desc
-	Services *prometheus.Desc
+	ServiceRevisions                *prometheus.Desc
+	ServiceServingRevisions         *prometheus.Desc
+	ServiceTrafficPercent           *prometheus.Desc
+	ServiceOldestServingRevisionAge *prometheus.Desc
+
+	JobExecutions                       *prometheus.Desc
+	JobLastExecutionCompletionTimestamp *prometheus.Desc
+	JobLastExecutionSucceeded           *prometheus.Desc
 }
 
 // NewCloudRunCollector returns a new CloudRunCollector
-func NewCloudRunCollector(account *gcp.Account) (*CloudRunCollector, error) {
+func NewCloudRunCollector(account *gcp.Account, lookback time.Duration) (*CloudRunCollector, error) {
 	subsystem := "cloud_run"
 
 	ctx := context.Background()
@@ -39,26 +70,116 @@ func NewCloudRunCollector(account *gcp.Account) (*CloudRunCollector, error) {
 		return nil, err
 	}
 
+	serviceLabels := []string{
+		"project",
+		"region",
+		"service",
+	}
+	jobLabels := []string{
+		"project",
+		"region",
+		"job_name",
+	}
+
 	return &CloudRunCollector{
 		account:         account,
 		cloudrunService: cloudrunService,
+		lookback:        lookback,
 
 		Jobs: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, subsystem, "jobs"),
 			"Number of Jobs",
-			[]string{
+			withResourceLabels(account,
 				"project",
-				// "region",
-			},
+				"region",
+			),
 			nil,
 		),
 		Services: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, subsystem, "services"),
 			"Number of Services",
-			[]string{
+			withResourceLabels(account,
 				"project",
-				// "region",
-			},
+				"region",
+			),
+			nil,
+		),
+		ServiceInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "service_info"),
+			"Service information including the latest (ready) revision and ingress",
+			withResourceLabels(account, append(serviceLabels, "latest_revision", "ingress")...),
+			nil,
+		),
+		ServiceReady: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "service_ready"),
+			"1 if the service's Ready condition succeeded, 0 otherwise",
+			withResourceLabels(account, serviceLabels...),
+			nil,
+		),
+		ServiceMinInstances: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "service_min_instances"),
+			"Minimum number of instances of the service",
+			withResourceLabels(account, serviceLabels...),
+			nil,
+		),
+		ServiceMaxInstances: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "service_max_instances"),
+			"Maximum number of instances of the service (0 if not set)",
+			withResourceLabels(account, serviceLabels...),
+			nil,
+		),
+		ServiceLastDeployedTimestamp: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "service_last_deployed_timestamp_seconds"),
+			"Time the service was last deployed (updated) in Unix epoch seconds",
+			withResourceLabels(account, serviceLabels...),
+			nil,
+		),
+		ServicePublic: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "service_public"),
+			"1 if allUsers or allAuthenticatedUsers may invoke the service, 0 otherwise",
+			withResourceLabels(account, serviceLabels...),
+			nil,
+		),
+		ServiceRevisions: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "service_revisions"),
+			"Number of the service's revisions",
+			withResourceLabels(account, serviceLabels...),
+			nil,
+		),
+		ServiceServingRevisions: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "service_serving_revisions"),
+			"Number of the service's revisions receiving traffic",
+			withResourceLabels(account, serviceLabels...),
+			nil,
+		),
+		ServiceTrafficPercent: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "service_traffic_percent"),
+			"Percent of the service's traffic assigned to the revision (and tag)",
+			withResourceLabels(account, append(serviceLabels, "revision", "tag")...),
+			nil,
+		),
+		ServiceOldestServingRevisionAge: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "service_oldest_serving_revision_age_seconds"),
+			"Age of the service's oldest revision receiving traffic in seconds",
+			withResourceLabels(account, serviceLabels...),
+			nil,
+		),
+		JobExecutions: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "job_executions"),
+			"Number of the job's executions created within the lookback window by status (succeeded|failed|running)",
+			withResourceLabels(account, append(jobLabels, "status")...),
+			nil,
+		),
+		JobLastExecutionCompletionTimestamp: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "job_last_execution_completion_timestamp_seconds"),
+			"Time the job's last completed execution (within the lookback window) completed in Unix epoch seconds",
+			withResourceLabels(account, jobLabels...),
+			nil,
+		),
+		JobLastExecutionSucceeded: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "job_last_execution_succeeded"),
+			"1 if the job's last completed execution (within the lookback window) succeeded, 0 if it failed",
+			withResourceLabels(account, jobLabels...),
 			nil,
 		),
 	}, nil
@@ -66,67 +187,68 @@ func NewCloudRunCollector(account *gcp.Account) (*CloudRunCollector, error) {
 
 // Collect implements Prometheus' Collector interface and is used to collect metrics
 func (c *CloudRunCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx := context.Background()
+
 	// Enumerate all of the projects
 	// WaitGroup is used for project Services|Jobs
 	var wg sync.WaitGroup
 	for _, p := range c.account.Projects {
 		log.Printf("[CloudRunCollector] Project: %s", p.ProjectId)
 
-		parent := fmt.Sprintf("namespaces/%s", p.ProjectId)
+		// Cloud Run Admin API v2 lists resources across all locations (regions) using the `-` wildcard
+		parent := fmt.Sprintf("projects/%s/locations/-", p.ProjectId)
 
 		// Cloud Run services
 		wg.Add(1)
 		go func(p *cloudresourcemanager.Project) {
 			defer wg.Done()
 
-			// ListServicesResponse may (!) contain Metadata
-			// If Metadata is presnet, it may (!) contain Continue iff there's more data
-			// https://pkg.go.dev/google.golang.org/api@v0.43.0/run/v1#ListServicesResponse
-			// https://pkg.go.dev/google.golang.org/api@v0.43.0/run/v1#ListMeta
-
-			rqst := c.cloudrunService.Namespaces.Services.List(parent)
-
-			// Do request at least once
-			cont := ""
-			count := 0
-			for {
-				rqst.Continue(cont)
-				resp, err := rqst.Do()
-				if err != nil {
-					if e, ok := err.(*googleapi.Error); ok {
-						if e.Code == http.StatusForbidden {
-							// Probably (!) Cloud Run Admin API has not been used in this project
-							return
-						}
-
-						log.Printf("Google API Error: %d [%s]", e.Code, e.Message)
-						return
-					}
-
-					log.Println(err)
-					return
-				}
+			// Counts are grouped by region and the allowed resource labels' values
+			counts := map[string]int{}
+			resources := []gcp.Resource{}
+
+			// WaitGroup is used for the services' revisions
+			var swg sync.WaitGroup
+
+			rqst := c.cloudrunService.Projects.Locations.Services.List(parent).PageSize(500)
+			if err := rqst.Pages(ctx, func(page *run.GoogleCloudRunV2ListServicesResponse) error {
+				for _, service := range page.Services {
+					resource := newCloudRunResource(p.ProjectId, "service", service.Name, service.Labels, service.TerminalCondition)
+					resourceLabels := c.account.Labels.Values(service.Labels)
+
+					counts[groupKey(append([]string{resource.Location}, resourceLabels...)...)]++
+					resources = append(resources, resource)
+
+					c.collectService(ch, p.ProjectId, resource.Location, service, resourceLabels)
 
-				pageSize := len(resp.Items)
-				count += pageSize
+					swg.Add(1)
+					go func(service *run.GoogleCloudRunV2Service, region string, resourceLabels []string) {
+						defer swg.Done()
+						c.collectRevisions(ctx, ch, p.ProjectId, region, service, resourceLabels)
+					}(service, resource.Location, resourceLabels)
 
-				if resp.Metadata != nil {
-					// If there's Metadata, update cont
-					cont = resp.Metadata.Continue
-				} else {
-					// Otherwise, we're done
-					break
+					swg.Add(1)
+					go func(service *run.GoogleCloudRunV2Service, region string, resourceLabels []string) {
+						defer swg.Done()
+						c.collectPublic(ctx, ch, p.ProjectId, region, service, resourceLabels)
+					}(service, resource.Location, resourceLabels)
 				}
+				return nil
+			}); err != nil {
+				logError("CloudRunCollector", p.ProjectId, err)
+				swg.Wait()
+				return
 			}
+			swg.Wait()
 
-			if count != 0 {
+			c.account.Inventory.Update(p.ProjectId, "cloud_run", "service", resources)
+
+			for k, count := range counts {
 				ch <- prometheus.MustNewConstMetric(
 					c.Services,
 					prometheus.GaugeValue,
 					float64(count),
-					[]string{
-						p.ProjectId,
-					}...,
+					append([]string{p.ProjectId}, groupValues(k)...)...,
 				)
 			}
 		}(p)
@@ -136,48 +258,44 @@ func (c *CloudRunCollector) Collect(ch chan<- prometheus.Metric) {
 		go func(p *cloudresourcemanager.Project) {
 			defer wg.Done()
 
-			rqst := c.cloudrunService.Namespaces.Jobs.List(parent)
-
-			// Do request at least once
-			cont := ""
-			count := 0
-			for {
-				rqst.Continue(cont)
-				resp, err := rqst.Do()
-				if err != nil {
-					if e, ok := err.(*googleapi.Error); ok {
-						if e.Code == http.StatusForbidden {
-							// Probably (!) Cloud Run Admin API has not been used in this project
-							return
-						}
-
-						log.Printf("Google API Error: %d [%s]", e.Code, e.Message)
-						return
-					}
-
-					log.Println(err)
-					return
-				}
+			// Counts are grouped by region and the allowed resource labels' values
+			counts := map[string]int{}
+			resources := []gcp.Resource{}
+
+			// WaitGroup is used for the jobs' executions
+			var jwg sync.WaitGroup
+
+			rqst := c.cloudrunService.Projects.Locations.Jobs.List(parent).PageSize(500)
+			if err := rqst.Pages(ctx, func(page *run.GoogleCloudRunV2ListJobsResponse) error {
+				for _, job := range page.Jobs {
+					resource := newCloudRunResource(p.ProjectId, "job", job.Name, job.Labels, job.TerminalCondition)
+					resourceLabels := c.account.Labels.Values(job.Labels)
 
-				pageSize := len(resp.Items)
-				count += pageSize
+					counts[groupKey(append([]string{resource.Location}, resourceLabels...)...)]++
+					resources = append(resources, resource)
 
-				if resp.Metadata != nil {
-					// If there's Metadata, update cont
-					cont = resp.Metadata.Continue
-				} else {
-					// We're done
-					break
+					jwg.Add(1)
+					go func(job *run.GoogleCloudRunV2Job, region string, resourceLabels []string) {
+						defer jwg.Done()
+						c.collectExecutions(ctx, ch, p.ProjectId, region, job, resourceLabels)
+					}(job, resource.Location, resourceLabels)
 				}
+				return nil
+			}); err != nil {
+				logError("CloudRunCollector", p.ProjectId, err)
+				jwg.Wait()
+				return
 			}
-			if count != 0 {
+			jwg.Wait()
+
+			c.account.Inventory.Update(p.ProjectId, "cloud_run", "job", resources)
+
+			for k, count := range counts {
 				ch <- prometheus.MustNewConstMetric(
 					c.Jobs,
 					prometheus.GaugeValue,
 					float64(count),
-					[]string{
-						p.ProjectId,
-					}...,
+					append([]string{p.ProjectId}, groupValues(k)...)...,
 				)
 			}
 		}(p)
@@ -185,8 +303,352 @@ func (c *CloudRunCollector) Collect(ch chan<- prometheus.Metric) {
 	wg.Wait()
 }
 
+// collectService collects a service's metrics
+func (c *CloudRunCollector) collectService(ch chan<- prometheus.Metric, project, region string, service *run.GoogleCloudRunV2Service, resourceLabels []string) {
+	labels := append([]string{
+		project,
+		region,
+		path.Base(service.Name),
+	}, resourceLabels...)
+
+	ch <- prometheus.MustNewConstMetric(
+		c.ServiceInfo,
+		prometheus.GaugeValue,
+		1.0,
+		append([]string{
+			project,
+			region,
+			path.Base(service.Name),
+			path.Base(service.LatestReadyRevision),
+			service.Ingress,
+		}, resourceLabels...)...,
+	)
+
+	ch <- prometheus.MustNewConstMetric(
+		c.ServiceReady,
+		prometheus.GaugeValue,
+		func(condition *run.GoogleCloudRunV2Condition) float64 {
+			if condition != nil && condition.State == "CONDITION_SUCCEEDED" {
+				return 1.0
+			}
+			return 0.0
+		}(service.TerminalCondition),
+		labels...,
+	)
+
+	// Instance limits are set on the service (scaling) and its revision template (scaling)
+	// The service's settings take precedence
+	var minInstances, maxInstances int64
+	if service.Template != nil && service.Template.Scaling != nil {
+		minInstances = service.Template.Scaling.MinInstanceCount
+		maxInstances = service.Template.Scaling.MaxInstanceCount
+	}
+	if service.Scaling != nil {
+		if service.Scaling.MinInstanceCount != 0 {
+			minInstances = service.Scaling.MinInstanceCount
+		}
+		if service.Scaling.MaxInstanceCount != 0 {
+			maxInstances = service.Scaling.MaxInstanceCount
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(
+		c.ServiceMinInstances,
+		prometheus.GaugeValue,
+		float64(minInstances),
+		labels...,
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.ServiceMaxInstances,
+		prometheus.GaugeValue,
+		float64(maxInstances),
+		labels...,
+	)
+
+	if t, err := time.Parse(time.RFC3339, service.UpdateTime); err == nil {
+		ch <- prometheus.MustNewConstMetric(
+			c.ServiceLastDeployedTimestamp,
+			prometheus.GaugeValue,
+			float64(t.Unix()),
+			labels...,
+		)
+	}
+
+	// Traffic is keyed by revision and tag
+	traffic := servingTraffic(service)
+	for k, percent := range traffic {
+		ch <- prometheus.MustNewConstMetric(
+			c.ServiceTrafficPercent,
+			prometheus.GaugeValue,
+			float64(percent),
+			append([]string{
+				project,
+				region,
+				path.Base(service.Name),
+				k[0],
+				k[1],
+			}, resourceLabels...)...,
+		)
+	}
+	ch <- prometheus.MustNewConstMetric(
+		c.ServiceServingRevisions,
+		prometheus.GaugeValue,
+		float64(len(servingRevisions(traffic))),
+		labels...,
+	)
+}
+
+// servingTraffic returns the percent of the service's (actual) traffic by revision and tag
+// Traffic assigned to the latest revision is attributed to the latest ready revision
+func servingTraffic(service *run.GoogleCloudRunV2Service) map[[2]string]int64 {
+	traffic := map[[2]string]int64{}
+	for _, status := range service.TrafficStatuses {
+		revision := status.Revision
+		if status.Type == "TRAFFIC_TARGET_ALLOCATION_TYPE_LATEST" {
+			revision = service.LatestReadyRevision
+		}
+		if revision == "" {
+			continue
+		}
+		traffic[[2]string{path.Base(revision), status.Tag}] += status.Percent
+	}
+	return traffic
+}
+
+// servingRevisions returns the revisions that receive traffic
+func servingRevisions(traffic map[[2]string]int64) map[string]bool {
+	serving := map[string]bool{}
+	for k, percent := range traffic {
+		if percent > 0 {
+			serving[k[0]] = true
+		}
+	}
+	return serving
+}
+
+// collectRevisions collects the number of a service's revisions and the age of its oldest revision receiving traffic
+func (c *CloudRunCollector) collectRevisions(ctx context.Context, ch chan<- prometheus.Metric, project, region string, service *run.GoogleCloudRunV2Service, resourceLabels []string) {
+	serving := servingRevisions(servingTraffic(service))
+
+	count := 0
+	var oldest time.Time
+
+	rqst := c.cloudrunService.Projects.Locations.Services.Revisions.List(service.Name).PageSize(500)
+	if err := rqst.Pages(ctx, func(page *run.GoogleCloudRunV2ListRevisionsResponse) error {
+		for _, revision := range page.Revisions {
+			count++
+			if !serving[path.Base(revision.Name)] {
+				continue
+			}
+			created, err := time.Parse(time.RFC3339, revision.CreateTime)
+			if err != nil {
+				continue
+			}
+			if oldest.IsZero() || created.Before(oldest) {
+				oldest = created
+			}
+		}
+		return nil
+	}); err != nil {
+		logError("CloudRunCollector", project, err)
+		return
+	}
+
+	labels := append([]string{
+		project,
+		region,
+		path.Base(service.Name),
+	}, resourceLabels...)
+
+	ch <- prometheus.MustNewConstMetric(
+		c.ServiceRevisions,
+		prometheus.GaugeValue,
+		float64(count),
+		labels...,
+	)
+
+	if oldest.IsZero() {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(
+		c.ServiceOldestServingRevisionAge,
+		prometheus.GaugeValue,
+		time.Since(oldest).Seconds(),
+		labels...,
+	)
+}
+
+// collectPublic collects whether a service's IAM policy permits anyone to invoke it
+func (c *CloudRunCollector) collectPublic(ctx context.Context, ch chan<- prometheus.Metric, project, region string, service *run.GoogleCloudRunV2Service, resourceLabels []string) {
+	policy, err := c.cloudrunService.Projects.Locations.Services.GetIamPolicy(service.Name).Context(ctx).Do()
+	if err != nil {
+		logError("CloudRunCollector", project, err)
+		return
+	}
+
+	public := 0.0
+	for _, binding := range policy.Bindings {
+		if invokerRoles[binding.Role] && hasPublicMember(binding.Members) {
+			public = 1.0
+			break
+		}
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		c.ServicePublic,
+		prometheus.GaugeValue,
+		public,
+		append([]string{
+			project,
+			region,
+			path.Base(service.Name),
+		}, resourceLabels...)...,
+	)
+}
+
+// collectExecutions collects a job's executions that were created within the lookback window
+// Executions that have not completed are running
+// Completed executions succeeded if their Completed condition succeeded and failed otherwise (including cancelled)
+// Executions are listed newest first so listing stops at the first execution created before the lookback window
+func (c *CloudRunCollector) collectExecutions(ctx context.Context, ch chan<- prometheus.Metric, project, region string, job *run.GoogleCloudRunV2Job, resourceLabels []string) {
+	cutoff := time.Now().Add(-c.lookback)
+
+	statuses := map[string]int{
+		"succeeded": 0,
+		"failed":    0,
+		"running":   0,
+	}
+	// The last completed execution is the one with the latest completion time
+	var lastCompletion time.Time
+	var lastSucceeded bool
+
+	rqst := c.cloudrunService.Projects.Locations.Jobs.Executions.List(job.Name).PageSize(500)
+	if err := rqst.Pages(ctx, func(page *run.GoogleCloudRunV2ListExecutionsResponse) error {
+		for _, execution := range page.Executions {
+			created, err := time.Parse(time.RFC3339, execution.CreateTime)
+			if err != nil {
+				continue
+			}
+			if created.Before(cutoff) {
+				return errLookbackExceeded
+			}
+
+			completed, err := time.Parse(time.RFC3339, execution.CompletionTime)
+			if err != nil {
+				statuses["running"]++
+				continue
+			}
+
+			succeeded := executionSucceeded(execution)
+			if succeeded {
+				statuses["succeeded"]++
+			} else {
+				statuses["failed"]++
+			}
+
+			if completed.After(lastCompletion) {
+				lastCompletion = completed
+				lastSucceeded = succeeded
+			}
+		}
+		return nil
+	}); err != nil && !errors.Is(err, errLookbackExceeded) {
+		logError("CloudRunCollector", project, err)
+		return
+	}
+
+	labels := append([]string{
+		project,
+		region,
+		path.Base(job.Name),
+	}, resourceLabels...)
+
+	for status, count := range statuses {
+		ch <- prometheus.MustNewConstMetric(
+			c.JobExecutions,
+			prometheus.GaugeValue,
+			float64(count),
+			append([]string{
+				project,
+				region,
+				path.Base(job.Name),
+				status,
+			}, resourceLabels...)...,
+		)
+	}
+
+	if lastCompletion.IsZero() {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(
+		c.JobLastExecutionCompletionTimestamp,
+		prometheus.GaugeValue,
+		float64(lastCompletion.Unix()),
+		labels...,
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.JobLastExecutionSucceeded,
+		prometheus.GaugeValue,
+		func(succeeded bool) float64 {
+			if succeeded {
+				return 1.0
+			}
+			return 0.0
+		}(lastSucceeded),
+		labels...,
+	)
+}
+
+// executionSucceeded returns whether a (completed) execution's Completed condition succeeded
+func executionSucceeded(execution *run.GoogleCloudRunV2Execution) bool {
+	for _, condition := range execution.Conditions {
+		if condition.Type == "Completed" {
+			return condition.State == "CONDITION_SUCCEEDED"
+		}
+	}
+	return false
+}
+
+// newCloudRunResource converts a Cloud Run resource into an inventory Resource
+// Name == projects/{project}/locations/{location}/{services|jobs}/{name}
+// The state is derived from the resource's terminal (Ready) condition
+func newCloudRunResource(project, resourceType, name string, labels map[string]string, condition *run.GoogleCloudRunV2Condition) gcp.Resource {
+	resource := gcp.Resource{
+		Project:  project,
+		Service:  "cloud_run",
+		Type:     resourceType,
+		Location: locationOf(name),
+		Name:     path.Base(name),
+		State:    "UNKNOWN",
+		Labels:   labels,
+	}
+
+	if condition != nil {
+		switch condition.State {
+		case "CONDITION_SUCCEEDED":
+			resource.State = "READY"
+		case "CONDITION_FAILED":
+			resource.State = "NOT_READY"
+		}
+	}
+
+	return resource
+}
+
 // Describe implements Prometheus' Collector interface and is used to describe metrics
 func (c *CloudRunCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.Services
 	ch <- c.Jobs
+	ch <- c.ServiceInfo
+	ch <- c.ServiceReady
+	ch <- c.ServiceMinInstances
+	ch <- c.ServiceMaxInstances
+	ch <- c.ServiceLastDeployedTimestamp
+	ch <- c.ServicePublic
+	ch <- c.ServiceRevisions
+	ch <- c.ServiceServingRevisions
+	ch <- c.ServiceTrafficPercent
+	ch <- c.ServiceOldestServingRevisionAge
+	ch <- c.JobExecutions
+	ch <- c.JobLastExecutionCompletionTimestamp
+	ch <- c.JobLastExecutionSucceeded
 }
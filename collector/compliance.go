@@ -0,0 +1,111 @@
+package collector
+
+import (
+	"strings"
+
+	"github.com/DazWilkin/gcp-exporter/gcp"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	_ prometheus.Collector = (*LabelComplianceCollector)(nil)
+)
+
+var (
+	// labelableResources are the Inventory's (service, type) resources that support labels
+	// Other resources (e.g. service accounts, firewall rules) cannot be labeled and are not checked
+	labelableResources = map[[2]string]bool{
+		{"artifact_registry", "repository"}: true,
+		{"cloud_run", "job"}:                true,
+		{"cloud_run", "service"}:            true,
+		{"compute", "address"}:              true,
+		{"compute", "disk"}:                 true,
+		{"compute", "forwarding_rule"}:      true,
+		{"compute", "image"}:                true,
+		{"compute", "instance"}:             true,
+		{"compute", "snapshot"}:             true,
+		{"eventarc", "channel"}:             true,
+		{"eventarc", "trigger"}:             true,
+		{"functions", "function"}:           true,
+		{"gke", "cluster"}:                  true,
+		{"pubsub", "subscription"}:          true,
+		{"pubsub", "topic"}:                 true,
+		{"storage", "bucket"}:               true,
+	}
+)
+
+// LabelComplianceCollector represents labelable resources that are missing required labels
+// Resources are those enumerated (by the other collectors) in the Inventory
+type LabelComplianceCollector struct {
+	account  *gcp.Account
+	required []string
+
+	MissingRequiredLabels *prometheus.Desc
+}
+
+// NewLabelComplianceCollector returns a new LabelComplianceCollector
+func NewLabelComplianceCollector(account *gcp.Account, required []string) *LabelComplianceCollector {
+	subsystem := "resources"
+
+	labels := []string{}
+	for _, label := range required {
+		if label = strings.TrimSpace(label); label != "" {
+			labels = append(labels, label)
+		}
+	}
+
+	return &LabelComplianceCollector{
+		account:  account,
+		required: labels,
+
+		MissingRequiredLabels: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "missing_required_labels"),
+			"Number of labelable resources that are missing a required label (or whose value is empty)",
+			[]string{
+				"project",
+				"service",
+				"label",
+			},
+			nil,
+		),
+	}
+}
+
+// Collect implements Prometheus' Collector interface and is used to collect metrics
+func (c *LabelComplianceCollector) Collect(ch chan<- prometheus.Metric) {
+	// Counts are by project, service and required label
+	// Services with labelable resources report 0 for the required labels that no resource is missing
+	counts := map[[3]string]int{}
+	for _, resource := range c.account.Inventory.Resources() {
+		if !labelableResources[[2]string{resource.Service, resource.Type}] {
+			continue
+		}
+		for _, label := range c.required {
+			k := [3]string{resource.Project, resource.Service, label}
+			if _, ok := counts[k]; !ok {
+				counts[k] = 0
+			}
+			if value, ok := resource.Labels[label]; !ok || value == "" {
+				counts[k]++
+			}
+		}
+	}
+
+	for k, count := range counts {
+		ch <- prometheus.MustNewConstMetric(
+			c.MissingRequiredLabels,
+			prometheus.GaugeValue,
+			float64(count),
+			[]string{
+				k[0],
+				k[1],
+				k[2],
+			}...,
+		)
+	}
+}
+
+// Describe implements Prometheus' Collector interface and is used to describe metrics
+func (c *LabelComplianceCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.MissingRequiredLabels
+}
@@ -3,7 +3,11 @@ package collector
 import (
 	"context"
 	"log"
+	"path"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/DazWilkin/gcp-exporter/gcp"
 	"github.com/prometheus/client_golang/prometheus"
@@ -18,12 +22,37 @@ type ComputeCollector struct {
 	account        *gcp.Account
 	computeService *compute.Service
 
-	Instances       *prometheus.Desc
-	ForwardingRules *prometheus.Desc
+	enableInstanceInfo bool
+
+	Instances                  *prometheus.Desc
+	InstancesByStatus          *prometheus.Desc
+	InstanceInfo               *prometheus.Desc
+	InstanceCreationTimestamp  *prometheus.Desc
+	InstanceLastStartTimestamp *prometheus.Desc
+	ForwardingRules            *prometheus.Desc
+	QuotaLimit                 *prometheus.Desc
+	QuotaUsage                 *prometheus.Desc
+	Addresses                  *prometheus.Desc
+	FirewallRules              *prometheus.Desc
+	FirewallRulesOpen          *prometheus.Desc
+	FirewallRulesRiskyPorts    *prometheus.Desc
 }
 
+var (
+	// riskyPorts are well-known ports that should not be open to the internet
+	riskyPorts = map[int64]string{
+		22:    "ssh",
+		1433:  "mssql",
+		3306:  "mysql",
+		3389:  "rdp",
+		5432:  "postgresql",
+		6379:  "redis",
+		27017: "mongodb",
+	}
+)
+
 // NewComputeCollector returns a new ComputeCollector
-func NewComputeCollector(account *gcp.Account) (*ComputeCollector, error) {
+func NewComputeCollector(account *gcp.Account, enableInstanceInfo bool) (*ComputeCollector, error) {
 	subsystem := "compute_engine"
 
 	ctx := context.Background()
@@ -37,21 +66,119 @@ func NewComputeCollector(account *gcp.Account) (*ComputeCollector, error) {
 		account:        account,
 		computeService: computeService,
 
+		enableInstanceInfo: enableInstanceInfo,
+
 		Instances: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, subsystem, "instances"),
 			"Number of instances",
-			[]string{
+			withResourceLabels(account,
 				"project",
 				"zone",
-			},
+			),
+			nil,
+		),
+		InstancesByStatus: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "instances_by_status"),
+			"Number of instances by status, machine type, provisioning model and CPU platform",
+			withResourceLabels(account,
+				"project",
+				"zone",
+				"status",
+				"machine_type",
+				"provisioning_model",
+				"cpu_platform",
+			),
+			nil,
+		),
+		InstanceInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "instance_info"),
+			"Instance information. 1 if the instance is running, 0 otherwise",
+			withResourceLabels(account, "project", "zone", "name", "status", "machine_type", "provisioning_model", "cpu_platform"),
+			nil,
+		),
+		InstanceCreationTimestamp: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "instance_creation_timestamp_seconds"),
+			"Instance creation time in Unix epoch seconds",
+			withResourceLabels(account, "project", "zone", "name"),
+			nil,
+		),
+		InstanceLastStartTimestamp: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "instance_last_start_timestamp_seconds"),
+			"Instance last start time in Unix epoch seconds",
+			withResourceLabels(account, "project", "zone", "name"),
 			nil,
 		),
 		ForwardingRules: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, subsystem, "forwardingrules"),
 			"Number of forwardingrules",
+			withResourceLabels(account,
+				"project",
+				"region",
+			),
+			nil,
+		),
+		QuotaLimit: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "quota_limit"),
+			"Quota limit by metric (region is global for project-wide quotas)",
 			[]string{
 				"project",
 				"region",
+				"metric",
+			},
+			nil,
+		),
+		QuotaUsage: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "quota_usage"),
+			"Quota usage by metric (region is global for project-wide quotas)",
+			[]string{
+				"project",
+				"region",
+				"metric",
+			},
+			nil,
+		),
+		Addresses: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "addresses"),
+			"Number of reserved IP addresses by type (EXTERNAL|INTERNAL) and status (IN_USE|RESERVED) (region is global for global addresses)",
+			withResourceLabels(account,
+				"project",
+				"region",
+				"address_type",
+				"status",
+			),
+			nil,
+		),
+		FirewallRules: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "firewall_rules"),
+			"Number of VPC firewall rules",
+			[]string{
+				"project",
+				"network",
+				"direction",
+				"disabled",
+			},
+			nil,
+		),
+		FirewallRulesOpen: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "firewall_rules_open_to_internet"),
+			"Number of enabled ingress VPC firewall rules that allow traffic from any source (0.0.0.0/0 or ::/0)",
+			[]string{
+				"project",
+				"network",
+				"protocol",
+				"port_range",
+			},
+			nil,
+		),
+		FirewallRulesRiskyPorts: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "firewall_rules_risky_ports_open_to_internet"),
+			"Number of enabled ingress VPC firewall rules that allow traffic from any source (0.0.0.0/0 or ::/0) to a well-known risky port",
+			[]string{
+				"project",
+				"network",
+				"protocol",
+				"port",
+				"service",
 			},
 			nil,
 		),
@@ -63,7 +190,7 @@ func (c *ComputeCollector) Collect(ch chan<- prometheus.Metric) {
 	ctx := context.Background()
 
 	// Enumerate all of the projects
-	// WaitGroup is used for project Instances|ForwardingRules only (not the projects themselves)
+	// WaitGroup is used for project Instances|ForwardingRules|Quotas only (not the projects themselves)
 	var wg sync.WaitGroup
 	for _, p := range c.account.Projects {
 		log.Printf("[ComputeCollector] Project: %s", p.ProjectId)
@@ -71,52 +198,110 @@ func (c *ComputeCollector) Collect(ch chan<- prometheus.Metric) {
 		wg.Add(1)
 		go func(p *cloudresourcemanager.Project) {
 			defer wg.Done()
-			// Compute Engine API instances.list requires zone
-			// Must repeat the call for all possible zones
-			zoneList, err := c.computeService.Zones.List(p.ProjectId).Context(ctx).Do()
-			if err != nil {
-				if e, ok := err.(*googleapi.Error); ok {
-					log.Printf("[ComputeCollector] Project: %s -- Zones.List (%d)", p.ProjectId, e.Code)
+			// Compute Engine API instances.aggregatedList returns instances for all zones
+			// Partial success returns the instances of the reachable zones
+			instances := map[string][]*compute.Instance{}
+			resources := []gcp.Resource{}
+			// The inventory is not updated if any zone is unreachable to avoid reporting its resources as deleted
+			failed := false
+
+			rqst := c.computeService.Instances.AggregatedList(p.ProjectId).MaxResults(500).ReturnPartialSuccess(true)
+			if err := rqst.Pages(ctx, func(page *compute.InstanceAggregatedList) error {
+				if len(page.Unreachables) != 0 {
+					log.Printf("[ComputeCollector] Project: %s -- Instances.AggregatedList unreachable: %v", p.ProjectId, page.Unreachables)
+					failed = true
+				}
+				// Items are keyed by scope e.g. zones/{zone}
+				for scope, scoped := range page.Items {
+					zone := strings.TrimPrefix(scope, "zones/")
+					instances[zone] = append(instances[zone], scoped.Instances...)
+					for _, instance := range scoped.Instances {
+						resources = append(resources, gcp.Resource{
+							Project:  p.ProjectId,
+							Service:  "compute",
+							Type:     "instance",
+							Location: zone,
+							Name:     instance.Name,
+							State:    instance.Status,
+							Labels:   instance.Labels,
+						})
+					}
 				}
+				return nil
+			}); err != nil {
+				logError("ComputeCollector", p.ProjectId, err)
 				return
 			}
-			for _, z := range zoneList.Items {
-				wg.Add(1)
-				go func(z *compute.Zone) {
-					defer wg.Done()
-					rqst := c.computeService.Instances.List(p.ProjectId, z.Name).MaxResults(500)
-					count := 0
-					// Page through more results
-					if err := rqst.Pages(ctx, func(page *compute.InstanceList) error {
-						count += len(page.Items)
-						// for _, instance := range page.Items {
-						// 	instance.
-						// }
-						return nil
-					}); err != nil {
-						log.Println(err)
-						return
+
+			for zone, zoneInstances := range instances {
+				c.collectInstances(ch, p.ProjectId, zone, zoneInstances)
+			}
+
+			if !failed {
+				c.account.Inventory.Update(p.ProjectId, "compute", "instance", resources)
+			}
+		}(p)
+
+		wg.Add(1)
+		go func(p *cloudresourcemanager.Project) {
+			defer wg.Done()
+			// Compute Engine API forwardingRules.aggregatedList returns forwarding rules for all regions
+			// Partial success returns the forwarding rules of the reachable regions
+			// Counts are grouped by region and the allowed resource labels' values
+			counts := map[string]int{}
+			resources := []gcp.Resource{}
+			// The inventory is not updated if any region is unreachable to avoid reporting its resources as deleted
+			failed := false
+
+			rqst := c.computeService.ForwardingRules.AggregatedList(p.ProjectId).MaxResults(500).ReturnPartialSuccess(true)
+			if err := rqst.Pages(ctx, func(page *compute.ForwardingRuleAggregatedList) error {
+				if len(page.Unreachables) != 0 {
+					log.Printf("[ComputeCollector] Project: %s -- ForwardingRules.AggregatedList unreachable: %v", p.ProjectId, page.Unreachables)
+					failed = true
+				}
+				// Items are keyed by scope e.g. regions/{region} (or global)
+				for scope, scoped := range page.Items {
+					// Global forwarding rules were not previously collected
+					if !strings.HasPrefix(scope, "regions/") {
+						continue
 					}
-					if count != 0 {
-						ch <- prometheus.MustNewConstMetric(
-							c.Instances,
-							prometheus.GaugeValue,
-							float64(count),
-							[]string{
-								p.ProjectId,
-								z.Name,
-							}...,
-						)
+					region := strings.TrimPrefix(scope, "regions/")
+					for _, rule := range scoped.ForwardingRules {
+						counts[groupKey(append([]string{region}, c.account.Labels.Values(rule.Labels)...)...)]++
+						resources = append(resources, gcp.Resource{
+							Project:  p.ProjectId,
+							Service:  "compute",
+							Type:     "forwarding_rule",
+							Location: region,
+							Name:     rule.Name,
+							Labels:   rule.Labels,
+						})
 					}
-				}(z)
+				}
+				return nil
+			}); err != nil {
+				logError("ComputeCollector", p.ProjectId, err)
+				return
+			}
+
+			for k, count := range counts {
+				ch <- prometheus.MustNewConstMetric(
+					c.ForwardingRules,
+					prometheus.GaugeValue,
+					float64(count),
+					append([]string{p.ProjectId}, groupValues(k)...)...,
+				)
+			}
+
+			if !failed {
+				c.account.Inventory.Update(p.ProjectId, "compute", "forwarding_rule", resources)
 			}
 		}(p)
 
 		wg.Add(1)
 		go func(p *cloudresourcemanager.Project) {
 			defer wg.Done()
-			// Compute Engine API forwardingrules.list requires region
-			// Must repeat call for all possible regions
+			// Compute Engine API regions.list includes the regional quotas
 			regionList, err := c.computeService.Regions.List(p.ProjectId).Context(ctx).Do()
 			if err != nil {
 				if e, ok := err.(*googleapi.Error); ok {
@@ -127,38 +312,371 @@ func (c *ComputeCollector) Collect(ch chan<- prometheus.Metric) {
 				return
 			}
 			for _, r := range regionList.Items {
-				wg.Add(1)
-				go func(r *compute.Region) {
-					defer wg.Done()
-					rqst := c.computeService.ForwardingRules.List(p.ProjectId, r.Name).MaxResults(500)
-					count := 0
-					if err := rqst.Pages(ctx, func(page *compute.ForwardingRuleList) error {
-						count += len(page.Items)
-						return nil
-					}); err != nil {
-						log.Println(err)
-						return
-					}
-					if count != 0 {
-						ch <- prometheus.MustNewConstMetric(
-							c.ForwardingRules,
-							prometheus.GaugeValue,
-							float64(count),
-							[]string{
-								p.ProjectId,
-								r.Name,
-							}...,
-						)
-					}
-				}(r)
+				c.collectQuotas(ch, p.ProjectId, r.Name, r.Quotas)
 			}
 		}(p)
+
+		wg.Add(1)
+		go func(p *cloudresourcemanager.Project) {
+			defer wg.Done()
+			// Compute Engine API projects.get includes the project-wide quotas
+			project, err := c.computeService.Projects.Get(p.ProjectId).Context(ctx).Do()
+			if err != nil {
+				if e, ok := err.(*googleapi.Error); ok {
+					log.Printf("[ComputeCollector] Project: %s -- Projects.Get (%d)", p.ProjectId, e.Code)
+				} else {
+					log.Println(err)
+				}
+				return
+			}
+			c.collectQuotas(ch, p.ProjectId, "global", project.Quotas)
+		}(p)
+
+		wg.Add(1)
+		go func(p *cloudresourcemanager.Project) {
+			defer wg.Done()
+			c.collectAddresses(ctx, ch, p)
+		}(p)
+
+		wg.Add(1)
+		go func(p *cloudresourcemanager.Project) {
+			defer wg.Done()
+			c.collectFirewalls(ctx, ch, p)
+		}(p)
 	}
 	wg.Wait()
 }
 
+// collectAddresses collects the project's regional and global reserved IP addresses
+// Addresses that are RESERVED are not in use but are charged
+func (c *ComputeCollector) collectAddresses(ctx context.Context, ch chan<- prometheus.Metric, p *cloudresourcemanager.Project) {
+	// Addresses are aggregated by region (or global), type, status and allowed resource labels
+	counts := map[string]int{}
+	resources := []gcp.Resource{}
+	// The inventory is not updated if any region is unreachable to avoid reporting its resources as deleted
+	failed := false
+
+	add := func(region string, address *compute.Address) {
+		counts[groupKey(append([]string{region, address.AddressType, address.Status}, c.account.Labels.Values(address.Labels)...)...)]++
+		resources = append(resources, gcp.Resource{
+			Project:  p.ProjectId,
+			Service:  "compute",
+			Type:     "address",
+			Location: region,
+			Name:     address.Name,
+			State:    address.Status,
+			Labels:   address.Labels,
+		})
+	}
+
+	rqst := c.computeService.Addresses.AggregatedList(p.ProjectId).MaxResults(500).ReturnPartialSuccess(true)
+	if err := rqst.Pages(ctx, func(page *compute.AddressAggregatedList) error {
+		if len(page.Unreachables) != 0 {
+			log.Printf("[ComputeCollector] Project: %s -- Addresses.AggregatedList unreachable: %v", p.ProjectId, page.Unreachables)
+			failed = true
+		}
+		// Items are keyed by scope e.g. regions/{region}
+		// Global addresses are listed separately
+		for scope, scoped := range page.Items {
+			if !strings.HasPrefix(scope, "regions/") {
+				continue
+			}
+			region := strings.TrimPrefix(scope, "regions/")
+			for _, address := range scoped.Addresses {
+				add(region, address)
+			}
+		}
+		return nil
+	}); err != nil {
+		logError("ComputeCollector", p.ProjectId, err)
+		return
+	}
+
+	grqst := c.computeService.GlobalAddresses.List(p.ProjectId).MaxResults(500)
+	if err := grqst.Pages(ctx, func(page *compute.AddressList) error {
+		for _, address := range page.Items {
+			add("global", address)
+		}
+		return nil
+	}); err != nil {
+		logError("ComputeCollector", p.ProjectId, err)
+		return
+	}
+
+	for k, count := range counts {
+		ch <- prometheus.MustNewConstMetric(
+			c.Addresses,
+			prometheus.GaugeValue,
+			float64(count),
+			append([]string{p.ProjectId}, groupValues(k)...)...,
+		)
+	}
+
+	if !failed {
+		c.account.Inventory.Update(p.ProjectId, "compute", "address", resources)
+	}
+}
+
+// collectFirewalls collects the project's VPC firewall rules
+// Enabled ingress rules that allow traffic from any source are open to the internet
+func (c *ComputeCollector) collectFirewalls(ctx context.Context, ch chan<- prometheus.Metric, p *cloudresourcemanager.Project) {
+	rules := map[[3]string]int{}
+	open := map[[3]string]int{}
+	risky := map[[4]string]int{}
+	resources := []gcp.Resource{}
+
+	rqst := c.computeService.Firewalls.List(p.ProjectId).MaxResults(500)
+	if err := rqst.Pages(ctx, func(page *compute.FirewallList) error {
+		for _, firewall := range page.Items {
+			network := path.Base(firewall.Network)
+			rules[[3]string{network, firewall.Direction, strconv.FormatBool(firewall.Disabled)}]++
+
+			resources = append(resources, gcp.Resource{
+				Project:  p.ProjectId,
+				Service:  "compute",
+				Type:     "firewall_rule",
+				Location: "global",
+				Name:     firewall.Name,
+				State: func(disabled bool) string {
+					if disabled {
+						return "DISABLED"
+					}
+					return "ENABLED"
+				}(firewall.Disabled),
+			})
+
+			if firewall.Disabled || firewall.Direction != "INGRESS" || !openToInternet(firewall.SourceRanges) {
+				continue
+			}
+
+			for _, allowed := range firewall.Allowed {
+				// Rules without ports allow all ports
+				ports := allowed.Ports
+				if len(ports) == 0 {
+					ports = []string{"all"}
+				}
+				for _, portRange := range ports {
+					open[[3]string{network, allowed.IPProtocol, portRange}]++
+
+					for port, service := range riskyPorts {
+						if !inPortRange(port, allowed.IPProtocol, portRange) {
+							continue
+						}
+						risky[[4]string{network, allowed.IPProtocol, strconv.FormatInt(port, 10), service}]++
+					}
+				}
+			}
+		}
+		return nil
+	}); err != nil {
+		logError("ComputeCollector", p.ProjectId, err)
+		return
+	}
+
+	for k, count := range rules {
+		ch <- prometheus.MustNewConstMetric(
+			c.FirewallRules,
+			prometheus.GaugeValue,
+			float64(count),
+			[]string{
+				p.ProjectId,
+				k[0],
+				k[1],
+				k[2],
+			}...,
+		)
+	}
+	for k, count := range open {
+		ch <- prometheus.MustNewConstMetric(
+			c.FirewallRulesOpen,
+			prometheus.GaugeValue,
+			float64(count),
+			[]string{
+				p.ProjectId,
+				k[0],
+				k[1],
+				k[2],
+			}...,
+		)
+	}
+	for k, count := range risky {
+		ch <- prometheus.MustNewConstMetric(
+			c.FirewallRulesRiskyPorts,
+			prometheus.GaugeValue,
+			float64(count),
+			[]string{
+				p.ProjectId,
+				k[0],
+				k[1],
+				k[2],
+				k[3],
+			}...,
+		)
+	}
+
+	c.account.Inventory.Update(p.ProjectId, "compute", "firewall_rule", resources)
+}
+
+// openToInternet returns true if the source ranges include any IPv4 or IPv6 address
+func openToInternet(sourceRanges []string) bool {
+	for _, sourceRange := range sourceRanges {
+		if sourceRange == "0.0.0.0/0" || sourceRange == "::/0" {
+			return true
+		}
+	}
+	return false
+}
+
+// inPortRange returns true if a firewall rule's protocol and port range (e.g. 22, 8000-9000, all) include a TCP port
+// Risky ports are TCP ports and so only the tcp (or its IANA protocol number 6) and all protocols are considered
+func inPortRange(port int64, protocol, portRange string) bool {
+	if protocol != "tcp" && protocol != "6" && protocol != "all" {
+		return false
+	}
+	if portRange == "all" {
+		return true
+	}
+
+	from, to, found := strings.Cut(portRange, "-")
+	if !found {
+		to = from
+	}
+	lo, err := strconv.ParseInt(from, 10, 64)
+	if err != nil {
+		return false
+	}
+	hi, err := strconv.ParseInt(to, 10, 64)
+	if err != nil {
+		return false
+	}
+	return lo <= port && port <= hi
+}
+
+// collectInstances collects metrics that count and breakdown a zone's instances
+// Per-instance metrics are only collected if enabled
+func (c *ComputeCollector) collectInstances(ch chan<- prometheus.Metric, project, zone string, instances []*compute.Instance) {
+	// Instances are grouped by their allowed resource labels' values
+	counts := map[string]int{}
+	breakdown := map[string]int{}
+	for _, instance := range instances {
+		status := instance.Status
+		machineType := path.Base(instance.MachineType)
+		provisioningModel := provisioningModelOf(instance.Scheduling)
+		cpuPlatform := instance.CpuPlatform
+		resourceLabels := c.account.Labels.Values(instance.Labels)
+
+		counts[groupKey(append([]string{zone}, resourceLabels...)...)]++
+		breakdown[groupKey(append([]string{zone, status, machineType, provisioningModel, cpuPlatform}, resourceLabels...)...)]++
+
+		if !c.enableInstanceInfo {
+			continue
+		}
+
+		labels := []string{
+			project,
+			zone,
+			instance.Name,
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			c.InstanceInfo,
+			prometheus.GaugeValue,
+			func(status string) float64 {
+				if status == "RUNNING" {
+					return 1.0
+				}
+				return 0.0
+			}(status),
+			append(append(labels, status, machineType, provisioningModel, cpuPlatform), resourceLabels...)...,
+		)
+
+		if t, err := time.Parse(time.RFC3339, instance.CreationTimestamp); err == nil {
+			ch <- prometheus.MustNewConstMetric(
+				c.InstanceCreationTimestamp,
+				prometheus.GaugeValue,
+				float64(t.Unix()),
+				append(labels, resourceLabels...)...,
+			)
+		}
+		// Instances that have never been started do not have a last start timestamp
+		if t, err := time.Parse(time.RFC3339, instance.LastStartTimestamp); err == nil {
+			ch <- prometheus.MustNewConstMetric(
+				c.InstanceLastStartTimestamp,
+				prometheus.GaugeValue,
+				float64(t.Unix()),
+				append(labels, resourceLabels...)...,
+			)
+		}
+	}
+
+	for k, count := range counts {
+		ch <- prometheus.MustNewConstMetric(
+			c.Instances,
+			prometheus.GaugeValue,
+			float64(count),
+			append([]string{project}, groupValues(k)...)...,
+		)
+	}
+	for k, count := range breakdown {
+		ch <- prometheus.MustNewConstMetric(
+			c.InstancesByStatus,
+			prometheus.GaugeValue,
+			float64(count),
+			append([]string{project}, groupValues(k)...)...,
+		)
+	}
+}
+
+// provisioningModelOf returns the provisioning model of an instance (SPOT|PREEMPTIBLE|STANDARD)
+// Preemptible instances predate provisioning models
+func provisioningModelOf(scheduling *compute.Scheduling) string {
+	if scheduling == nil {
+		return "STANDARD"
+	}
+	if scheduling.ProvisioningModel != "" && scheduling.ProvisioningModel != "STANDARD" {
+		return scheduling.ProvisioningModel
+	}
+	if scheduling.Preemptible {
+		return "PREEMPTIBLE"
+	}
+	return "STANDARD"
+}
+
+// collectQuotas collects quota limit and usage metrics
+func (c *ComputeCollector) collectQuotas(ch chan<- prometheus.Metric, project, region string, quotas []*compute.Quota) {
+	for _, quota := range quotas {
+		labels := []string{
+			project,
+			region,
+			quota.Metric,
+		}
+		ch <- prometheus.MustNewConstMetric(
+			c.QuotaLimit,
+			prometheus.GaugeValue,
+			quota.Limit,
+			labels...,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.QuotaUsage,
+			prometheus.GaugeValue,
+			quota.Usage,
+			labels...,
+		)
+	}
+}
+
 // Describe implements Prometheus' Collector interface and is used to describe metrics
 func (c *ComputeCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.Instances
+	ch <- c.InstancesByStatus
+	ch <- c.InstanceInfo
+	ch <- c.InstanceCreationTimestamp
+	ch <- c.InstanceLastStartTimestamp
 	ch <- c.ForwardingRules
+	ch <- c.QuotaLimit
+	ch <- c.QuotaUsage
+	ch <- c.Addresses
+	ch <- c.FirewallRules
+	ch <- c.FirewallRulesOpen
+	ch <- c.FirewallRulesRiskyPorts
 }
@@ -0,0 +1,335 @@
+package collector
+
+import (
+	"context"
+	"log"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DazWilkin/gcp-exporter/gcp"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"google.golang.org/api/cloudresourcemanager/v1"
+	"google.golang.org/api/compute/v1"
+)
+
+var (
+	_ prometheus.Collector = (*ComputeDisksCollector)(nil)
+)
+
+// ComputeDisksCollector represents Compute Engine persistent disks, snapshots and (custom) images
+type ComputeDisksCollector struct {
+	account        *gcp.Account
+	computeService *compute.Service
+
+	Disks                *prometheus.Desc
+	DiskSize             *prometheus.Desc
+	UnattachedDiskBytes  *prometheus.Desc
+	Snapshots            *prometheus.Desc
+	SnapshotAge          *prometheus.Desc
+	SnapshotStorageBytes *prometheus.Desc
+	Images               *prometheus.Desc
+}
+
+// NewComputeDisksCollector returns a new ComputeDisksCollector
+func NewComputeDisksCollector(account *gcp.Account) (*ComputeDisksCollector, error) {
+	subsystem := "compute_engine"
+
+	ctx := context.Background()
+	computeService, err := compute.NewService(ctx)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	diskLabels := withResourceLabels(account,
+		"project",
+		"zone",
+		"type",
+		"attached",
+	)
+	snapshotLabels := withResourceLabels(account,
+		"project",
+		"name",
+		"source_disk",
+	)
+
+	return &ComputeDisksCollector{
+		account:        account,
+		computeService: computeService,
+
+		Disks: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "disks"),
+			"Number of persistent disks (zone is the region for regional disks)",
+			diskLabels,
+			nil,
+		),
+		DiskSize: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "disk_size_gb"),
+			"Total size (GB) of persistent disks (zone is the region for regional disks)",
+			diskLabels,
+			nil,
+		),
+		UnattachedDiskBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "unattached_disk_bytes"),
+			"Total size (bytes) of persistent disks that are not attached to any instance",
+			withResourceLabels(account,
+				"project",
+				"zone",
+				"type",
+			),
+			nil,
+		),
+		Snapshots: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "snapshots"),
+			"Number of disk snapshots",
+			[]string{
+				"project",
+			},
+			nil,
+		),
+		SnapshotAge: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "snapshot_age_seconds"),
+			"Age of the disk snapshot in seconds",
+			snapshotLabels,
+			nil,
+		),
+		SnapshotStorageBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "snapshot_storage_bytes"),
+			"Size (bytes) of the storage used by the disk snapshot",
+			snapshotLabels,
+			nil,
+		),
+		Images: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "images"),
+			"Number of custom images",
+			withResourceLabels(account,
+				"project",
+				"family",
+				"status",
+			),
+			nil,
+		),
+	}, nil
+}
+
+// Collect implements Prometheus' Collector interface and is used to collect metrics
+func (c *ComputeDisksCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx := context.Background()
+
+	// Enumerate all of the projects
+	// WaitGroup is used for project Disks|Snapshots|Images only (not the projects themselves)
+	var wg sync.WaitGroup
+	for _, p := range c.account.Projects {
+		log.Printf("[ComputeDisksCollector] Project: %s", p.ProjectId)
+
+		wg.Add(1)
+		go func(p *cloudresourcemanager.Project) {
+			defer wg.Done()
+			c.collectDisks(ctx, ch, p)
+		}(p)
+
+		wg.Add(1)
+		go func(p *cloudresourcemanager.Project) {
+			defer wg.Done()
+			c.collectSnapshots(ctx, ch, p)
+		}(p)
+
+		wg.Add(1)
+		go func(p *cloudresourcemanager.Project) {
+			defer wg.Done()
+			c.collectImages(ctx, ch, p)
+		}(p)
+	}
+	wg.Wait()
+}
+
+// collectDisks collects the project's persistent disks
+// Disks are unattached if they have no users (instances)
+func (c *ComputeDisksCollector) collectDisks(ctx context.Context, ch chan<- prometheus.Metric, p *cloudresourcemanager.Project) {
+	// Disks are aggregated by zone (or region), type, whether attached and allowed resource labels
+	counts := map[string]int{}
+	sizes := map[string]int64{}
+
+	resources := []gcp.Resource{}
+	// The inventory is not updated if any zone is unreachable to avoid reporting its resources as deleted
+	failed := false
+
+	rqst := c.computeService.Disks.AggregatedList(p.ProjectId).MaxResults(500).ReturnPartialSuccess(true)
+	if err := rqst.Pages(ctx, func(page *compute.DiskAggregatedList) error {
+		if len(page.Unreachables) != 0 {
+			log.Printf("[ComputeDisksCollector] Project: %s -- Disks.AggregatedList unreachable: %v", p.ProjectId, page.Unreachables)
+			failed = true
+		}
+		// Items are keyed by scope e.g. zones/{zone} or regions/{region}
+		for scope, scoped := range page.Items {
+			location := path.Base(scope)
+			for _, disk := range scoped.Disks {
+				k := groupKey(append([]string{
+					location,
+					path.Base(disk.Type),
+					strconv.FormatBool(len(disk.Users) != 0),
+				}, c.account.Labels.Values(disk.Labels)...)...)
+				counts[k]++
+				sizes[k] += disk.SizeGb
+
+				resources = append(resources, gcp.Resource{
+					Project:  p.ProjectId,
+					Service:  "compute",
+					Type:     "disk",
+					Location: location,
+					Name:     disk.Name,
+					State:    disk.Status,
+					Labels:   disk.Labels,
+				})
+			}
+		}
+		return nil
+	}); err != nil {
+		logError("ComputeDisksCollector", p.ProjectId, err)
+		return
+	}
+
+	for k, count := range counts {
+		// values are zone, type, attached and the allowed resource labels' values
+		values := groupValues(k)
+		labels := append([]string{p.ProjectId}, values...)
+		ch <- prometheus.MustNewConstMetric(
+			c.Disks,
+			prometheus.GaugeValue,
+			float64(count),
+			labels...,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.DiskSize,
+			prometheus.GaugeValue,
+			float64(sizes[k]),
+			labels...,
+		)
+		if values[2] == "false" {
+			ch <- prometheus.MustNewConstMetric(
+				c.UnattachedDiskBytes,
+				prometheus.GaugeValue,
+				float64(sizes[k])*(1<<30),
+				append([]string{p.ProjectId, values[0], values[1]}, values[3:]...)...,
+			)
+		}
+	}
+
+	if !failed {
+		c.account.Inventory.Update(p.ProjectId, "compute", "disk", resources)
+	}
+}
+
+// collectSnapshots collects the project's disk snapshots
+func (c *ComputeDisksCollector) collectSnapshots(ctx context.Context, ch chan<- prometheus.Metric, p *cloudresourcemanager.Project) {
+	now := time.Now()
+	count := 0
+	resources := []gcp.Resource{}
+
+	rqst := c.computeService.Snapshots.List(p.ProjectId).MaxResults(500)
+	if err := rqst.Pages(ctx, func(page *compute.SnapshotList) error {
+		count += len(page.Items)
+		for _, snapshot := range page.Items {
+			labels := append([]string{
+				p.ProjectId,
+				snapshot.Name,
+				path.Base(snapshot.SourceDisk),
+			}, c.account.Labels.Values(snapshot.Labels)...)
+
+			if t, err := time.Parse(time.RFC3339, snapshot.CreationTimestamp); err == nil {
+				ch <- prometheus.MustNewConstMetric(
+					c.SnapshotAge,
+					prometheus.GaugeValue,
+					now.Sub(t).Seconds(),
+					labels...,
+				)
+			}
+			ch <- prometheus.MustNewConstMetric(
+				c.SnapshotStorageBytes,
+				prometheus.GaugeValue,
+				float64(snapshot.StorageBytes),
+				labels...,
+			)
+
+			resources = append(resources, gcp.Resource{
+				Project: p.ProjectId,
+				Service: "compute",
+				Type:    "snapshot",
+				// Snapshots are global resources stored in one or more (multi-)regions
+				Location: strings.Join(snapshot.StorageLocations, ","),
+				Name:     snapshot.Name,
+				State:    snapshot.Status,
+				Labels:   snapshot.Labels,
+			})
+		}
+		return nil
+	}); err != nil {
+		logError("ComputeDisksCollector", p.ProjectId, err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		c.Snapshots,
+		prometheus.GaugeValue,
+		float64(count),
+		[]string{
+			p.ProjectId,
+		}...,
+	)
+
+	c.account.Inventory.Update(p.ProjectId, "compute", "snapshot", resources)
+}
+
+// collectImages collects the project's custom images
+// Public images belong to other projects (e.g. debian-cloud) and are not listed
+func (c *ComputeDisksCollector) collectImages(ctx context.Context, ch chan<- prometheus.Metric, p *cloudresourcemanager.Project) {
+	counts := map[string]int{}
+	resources := []gcp.Resource{}
+
+	rqst := c.computeService.Images.List(p.ProjectId).MaxResults(500)
+	if err := rqst.Pages(ctx, func(page *compute.ImageList) error {
+		for _, image := range page.Items {
+			counts[groupKey(append([]string{image.Family, image.Status}, c.account.Labels.Values(image.Labels)...)...)]++
+
+			resources = append(resources, gcp.Resource{
+				Project:  p.ProjectId,
+				Service:  "compute",
+				Type:     "image",
+				Location: strings.Join(image.StorageLocations, ","),
+				Name:     image.Name,
+				State:    image.Status,
+				Labels:   image.Labels,
+			})
+		}
+		return nil
+	}); err != nil {
+		logError("ComputeDisksCollector", p.ProjectId, err)
+		return
+	}
+
+	for k, count := range counts {
+		ch <- prometheus.MustNewConstMetric(
+			c.Images,
+			prometheus.GaugeValue,
+			float64(count),
+			append([]string{p.ProjectId}, groupValues(k)...)...,
+		)
+	}
+
+	c.account.Inventory.Update(p.ProjectId, "compute", "image", resources)
+}
+
+// Describe implements Prometheus' Collector interface and is used to describe metrics
+func (c *ComputeDisksCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.Disks
+	ch <- c.DiskSize
+	ch <- c.UnattachedDiskBytes
+	ch <- c.Snapshots
+	ch <- c.SnapshotAge
+	ch <- c.SnapshotStorageBytes
+	ch <- c.Images
+}
@@ -0,0 +1,291 @@
+package collector
+
+import (
+	"context"
+	"log"
+	"path"
+	"strconv"
+	"sync"
+
+	"github.com/DazWilkin/gcp-exporter/gcp"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"google.golang.org/api/cloudresourcemanager/v1"
+	"google.golang.org/api/compute/v1"
+)
+
+var (
+	_ prometheus.Collector = (*ComputeInstanceGroupsCollector)(nil)
+)
+
+// ComputeInstanceGroupsCollector represents Compute Engine instance groups and managed instance groups (MIGs)
+type ComputeInstanceGroupsCollector struct {
+	account        *gcp.Account
+	computeService *compute.Service
+
+	InstanceGroups        *prometheus.Desc
+	TargetSize            *prometheus.Desc
+	CurrentSize           *prometheus.Desc
+	Stable                *prometheus.Desc
+	AutoscalerMinReplicas *prometheus.Desc
+	AutoscalerMaxReplicas *prometheus.Desc
+}
+
+// NewComputeInstanceGroupsCollector returns a new ComputeInstanceGroupsCollector
+func NewComputeInstanceGroupsCollector(account *gcp.Account) (*ComputeInstanceGroupsCollector, error) {
+	subsystem := "compute_engine"
+
+	ctx := context.Background()
+	computeService, err := compute.NewService(ctx)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	migLabels := []string{
+		"project",
+		"location",
+		"name",
+	}
+
+	return &ComputeInstanceGroupsCollector{
+		account:        account,
+		computeService: computeService,
+
+		InstanceGroups: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "instance_groups"),
+			"Number of instance groups (location is a zone or a region)",
+			[]string{
+				"project",
+				"location",
+				"managed",
+			},
+			nil,
+		),
+		TargetSize: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "instance_group_manager_target_size"),
+			"Target number of instances of the managed instance group",
+			migLabels,
+			nil,
+		),
+		CurrentSize: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "instance_group_manager_current_size"),
+			"Current number of instances of the managed instance group",
+			migLabels,
+			nil,
+		),
+		Stable: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "instance_group_manager_stable"),
+			"1 if the managed instance group is stable (all instances are running and have no pending actions), 0 otherwise",
+			migLabels,
+			nil,
+		),
+		AutoscalerMinReplicas: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "instance_group_manager_autoscaler_min_replicas"),
+			"Minimum number of instances to which the managed instance group's autoscaler can scale in",
+			migLabels,
+			nil,
+		),
+		AutoscalerMaxReplicas: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "instance_group_manager_autoscaler_max_replicas"),
+			"Maximum number of instances to which the managed instance group's autoscaler can scale out",
+			migLabels,
+			nil,
+		),
+	}, nil
+}
+
+// Collect implements Prometheus' Collector interface and is used to collect metrics
+func (c *ComputeInstanceGroupsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx := context.Background()
+
+	// Enumerate all of the projects
+	var wg sync.WaitGroup
+	for _, p := range c.account.Projects {
+		wg.Add(1)
+		go func(p *cloudresourcemanager.Project) {
+			defer wg.Done()
+			log.Printf("[ComputeInstanceGroupsCollector] Project: %s", p.ProjectId)
+
+			// Instance groups (managed and unmanaged) keyed by self link
+			// A managed instance group's current size is the size of its instance group
+			groups, failed := c.listInstanceGroups(ctx, p)
+			// Autoscalers keyed by their target (managed instance group) self link
+			autoscalers := c.listAutoscalers(ctx, p)
+
+			// Instance groups are managed if they're referenced by a managed instance group
+			// If the managed instance groups can't all be listed, instance groups can't be counted correctly
+			managed := map[string]bool{}
+			managedFailed := false
+			rqst := c.computeService.InstanceGroupManagers.AggregatedList(p.ProjectId).MaxResults(500).ReturnPartialSuccess(true)
+			if err := rqst.Pages(ctx, func(page *compute.InstanceGroupManagerAggregatedList) error {
+				if len(page.Unreachables) != 0 {
+					log.Printf("[ComputeInstanceGroupsCollector] Project: %s -- InstanceGroupManagers.AggregatedList unreachable: %v", p.ProjectId, page.Unreachables)
+					managedFailed = true
+				}
+				for _, scoped := range page.Items {
+					for _, mig := range scoped.InstanceGroupManagers {
+						managed[mig.InstanceGroup] = true
+						c.collectInstanceGroupManager(ch, p.ProjectId, mig, groups, autoscalers)
+					}
+				}
+				return nil
+			}); err != nil {
+				logError("ComputeInstanceGroupsCollector", p.ProjectId, err)
+				managedFailed = true
+			}
+
+			counts := map[[2]string]int{}
+			resources := []gcp.Resource{}
+			for selfLink, group := range groups {
+				location := locationOfScope(group.Zone, group.Region)
+				counts[[2]string{location, strconv.FormatBool(managed[selfLink])}]++
+
+				resources = append(resources, gcp.Resource{
+					Project:  p.ProjectId,
+					Service:  "compute",
+					Type:     "instance_group",
+					Location: location,
+					Name:     group.Name,
+				})
+			}
+			if !managedFailed {
+				for k, count := range counts {
+					ch <- prometheus.MustNewConstMetric(
+						c.InstanceGroups,
+						prometheus.GaugeValue,
+						float64(count),
+						[]string{
+							p.ProjectId,
+							k[0],
+							k[1],
+						}...,
+					)
+				}
+			}
+			if !failed {
+				c.account.Inventory.Update(p.ProjectId, "compute", "instance_group", resources)
+			}
+		}(p)
+	}
+	wg.Wait()
+}
+
+// collectInstanceGroupManager collects a managed instance group's metrics
+func (c *ComputeInstanceGroupsCollector) collectInstanceGroupManager(ch chan<- prometheus.Metric, project string, mig *compute.InstanceGroupManager, groups map[string]*compute.InstanceGroup, autoscalers map[string]*compute.Autoscaler) {
+	labels := []string{
+		project,
+		locationOfScope(mig.Zone, mig.Region),
+		mig.Name,
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		c.TargetSize,
+		prometheus.GaugeValue,
+		float64(mig.TargetSize),
+		labels...,
+	)
+
+	if group, ok := groups[mig.InstanceGroup]; ok {
+		ch <- prometheus.MustNewConstMetric(
+			c.CurrentSize,
+			prometheus.GaugeValue,
+			float64(group.Size),
+			labels...,
+		)
+	}
+
+	if mig.Status != nil {
+		ch <- prometheus.MustNewConstMetric(
+			c.Stable,
+			prometheus.GaugeValue,
+			func(stable bool) float64 {
+				if stable {
+					return 1.0
+				}
+				return 0.0
+			}(mig.Status.IsStable),
+			labels...,
+		)
+	}
+
+	// Managed instance groups need not be autoscaled
+	if autoscaler, ok := autoscalers[mig.SelfLink]; ok && autoscaler.AutoscalingPolicy != nil {
+		ch <- prometheus.MustNewConstMetric(
+			c.AutoscalerMinReplicas,
+			prometheus.GaugeValue,
+			float64(autoscaler.AutoscalingPolicy.MinNumReplicas),
+			labels...,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.AutoscalerMaxReplicas,
+			prometheus.GaugeValue,
+			float64(autoscaler.AutoscalingPolicy.MaxNumReplicas),
+			labels...,
+		)
+	}
+}
+
+// listInstanceGroups returns the project's instance groups keyed by self link
+// Returns true if the list is incomplete
+func (c *ComputeInstanceGroupsCollector) listInstanceGroups(ctx context.Context, p *cloudresourcemanager.Project) (map[string]*compute.InstanceGroup, bool) {
+	groups := map[string]*compute.InstanceGroup{}
+	failed := false
+
+	rqst := c.computeService.InstanceGroups.AggregatedList(p.ProjectId).MaxResults(500).ReturnPartialSuccess(true)
+	if err := rqst.Pages(ctx, func(page *compute.InstanceGroupAggregatedList) error {
+		if len(page.Unreachables) != 0 {
+			log.Printf("[ComputeInstanceGroupsCollector] Project: %s -- InstanceGroups.AggregatedList unreachable: %v", p.ProjectId, page.Unreachables)
+			failed = true
+		}
+		for _, scoped := range page.Items {
+			for _, group := range scoped.InstanceGroups {
+				groups[group.SelfLink] = group
+			}
+		}
+		return nil
+	}); err != nil {
+		logError("ComputeInstanceGroupsCollector", p.ProjectId, err)
+		failed = true
+	}
+
+	return groups, failed
+}
+
+// listAutoscalers returns the project's autoscalers keyed by their target's self link
+func (c *ComputeInstanceGroupsCollector) listAutoscalers(ctx context.Context, p *cloudresourcemanager.Project) map[string]*compute.Autoscaler {
+	autoscalers := map[string]*compute.Autoscaler{}
+
+	rqst := c.computeService.Autoscalers.AggregatedList(p.ProjectId).MaxResults(500).ReturnPartialSuccess(true)
+	if err := rqst.Pages(ctx, func(page *compute.AutoscalerAggregatedList) error {
+		for _, scoped := range page.Items {
+			for _, autoscaler := range scoped.Autoscalers {
+				autoscalers[autoscaler.Target] = autoscaler
+			}
+		}
+		return nil
+	}); err != nil {
+		logError("ComputeInstanceGroupsCollector", p.ProjectId, err)
+	}
+
+	return autoscalers
+}
+
+// locationOfScope returns the zone or region of a zonal or regional Compute Engine resource
+// Zone and Region are the resources' (self link) URLs
+func locationOfScope(zone, region string) string {
+	if zone != "" {
+		return path.Base(zone)
+	}
+	return path.Base(region)
+}
+
+// Describe implements Prometheus' Collector interface and is used to describe metrics
+func (c *ComputeInstanceGroupsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.InstanceGroups
+	ch <- c.TargetSize
+	ch <- c.CurrentSize
+	ch <- c.Stable
+	ch <- c.AutoscalerMinReplicas
+	ch <- c.AutoscalerMaxReplicas
+}
@@ -0,0 +1,349 @@
+package collector
+
+import (
+	"context"
+	"log"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/DazWilkin/gcp-exporter/gcp"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"google.golang.org/api/cloudresourcemanager/v1"
+	"google.golang.org/api/compute/v1"
+)
+
+var (
+	_ prometheus.Collector = (*ComputeLoadBalancingCollector)(nil)
+)
+
+// ComputeLoadBalancingCollector represents Cloud Load Balancing backend services, URL maps and target proxies
+type ComputeLoadBalancingCollector struct {
+	account        *gcp.Account
+	computeService *compute.Service
+
+	BackendServices *prometheus.Desc
+	BackendHealth   *prometheus.Desc
+	URLMaps         *prometheus.Desc
+	TargetProxies   *prometheus.Desc
+}
+
+// NewComputeLoadBalancingCollector returns a new ComputeLoadBalancingCollector
+func NewComputeLoadBalancingCollector(account *gcp.Account) (*ComputeLoadBalancingCollector, error) {
+	subsystem := "compute_engine"
+
+	ctx := context.Background()
+	computeService, err := compute.NewService(ctx)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return &ComputeLoadBalancingCollector{
+		account:        account,
+		computeService: computeService,
+
+		BackendServices: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "backend_services"),
+			"Number of backend services (region is global for global backend services)",
+			[]string{
+				"project",
+				"region",
+				"protocol",
+				"load_balancing_scheme",
+			},
+			nil,
+		),
+		BackendHealth: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "backend_health"),
+			"Number of backend service's group's instances (or endpoints) by health state (region is global for global backend services; location is the group's zone, region or global)",
+			[]string{
+				"project",
+				"region",
+				"backend_service",
+				"location",
+				"group",
+				"state",
+			},
+			nil,
+		),
+		URLMaps: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "url_maps"),
+			"Number of URL maps (region is global for global URL maps)",
+			[]string{
+				"project",
+				"region",
+			},
+			nil,
+		),
+		TargetProxies: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "target_proxies"),
+			"Number of target proxies by type (http|https|ssl|tcp) (region is global for global target proxies)",
+			[]string{
+				"project",
+				"region",
+				"type",
+			},
+			nil,
+		),
+	}, nil
+}
+
+// Collect implements Prometheus' Collector interface and is used to collect metrics
+func (c *ComputeLoadBalancingCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx := context.Background()
+
+	// Enumerate all of the projects
+	// WaitGroup is used for project BackendServices|URLMaps|TargetProxies only (not the projects themselves)
+	var wg sync.WaitGroup
+	for _, p := range c.account.Projects {
+		log.Printf("[ComputeLoadBalancingCollector] Project: %s", p.ProjectId)
+
+		wg.Add(1)
+		go func(p *cloudresourcemanager.Project) {
+			defer wg.Done()
+			c.collectBackendServices(ctx, ch, p)
+		}(p)
+
+		wg.Add(1)
+		go func(p *cloudresourcemanager.Project) {
+			defer wg.Done()
+			c.collectURLMaps(ctx, ch, p)
+		}(p)
+
+		wg.Add(1)
+		go func(p *cloudresourcemanager.Project) {
+			defer wg.Done()
+			c.collectTargetProxies(ctx, ch, p)
+		}(p)
+	}
+	wg.Wait()
+}
+
+// collectBackendServices collects the project's backend services and the health of their backends
+func (c *ComputeLoadBalancingCollector) collectBackendServices(ctx context.Context, ch chan<- prometheus.Metric, p *cloudresourcemanager.Project) {
+	counts := map[[3]string]int{}
+	resources := []gcp.Resource{}
+	// The inventory is not updated if any region is unreachable to avoid reporting its resources as deleted
+	failed := false
+
+	// Health is accumulated by backend service (and its region), group (and its location) and state
+	var mu sync.Mutex
+	health := map[[5]string]int{}
+
+	// WaitGroup is used for the backend services' health checks
+	var bwg sync.WaitGroup
+
+	rqst := c.computeService.BackendServices.AggregatedList(p.ProjectId).MaxResults(500).ReturnPartialSuccess(true)
+	if err := rqst.Pages(ctx, func(page *compute.BackendServiceAggregatedList) error {
+		if len(page.Unreachables) != 0 {
+			log.Printf("[ComputeLoadBalancingCollector] Project: %s -- BackendServices.AggregatedList unreachable: %v", p.ProjectId, page.Unreachables)
+			failed = true
+		}
+		// Items are keyed by scope e.g. global or regions/{region}
+		for scope, scoped := range page.Items {
+			region := path.Base(scope)
+			for _, service := range scoped.BackendServices {
+				counts[[3]string{region, service.Protocol, service.LoadBalancingScheme}]++
+
+				resources = append(resources, gcp.Resource{
+					Project:  p.ProjectId,
+					Service:  "compute",
+					Type:     "backend_service",
+					Location: region,
+					Name:     service.Name,
+				})
+
+				for _, backend := range service.Backends {
+					bwg.Add(1)
+					go func(service *compute.BackendService, backend *compute.Backend) {
+						defer bwg.Done()
+						states, err := c.getHealth(ctx, p.ProjectId, region, service.Name, backend.Group)
+						if err != nil {
+							logError("ComputeLoadBalancingCollector", p.ProjectId, err)
+							return
+						}
+						mu.Lock()
+						for state, count := range states {
+							health[[5]string{region, service.Name, locationOfGroup(backend.Group), path.Base(backend.Group), state}] += count
+						}
+						mu.Unlock()
+					}(service, backend)
+				}
+			}
+		}
+		return nil
+	}); err != nil {
+		logError("ComputeLoadBalancingCollector", p.ProjectId, err)
+		bwg.Wait()
+		return
+	}
+	bwg.Wait()
+
+	for k, count := range counts {
+		ch <- prometheus.MustNewConstMetric(
+			c.BackendServices,
+			prometheus.GaugeValue,
+			float64(count),
+			[]string{
+				p.ProjectId,
+				k[0],
+				k[1],
+				k[2],
+			}...,
+		)
+	}
+	for k, count := range health {
+		ch <- prometheus.MustNewConstMetric(
+			c.BackendHealth,
+			prometheus.GaugeValue,
+			float64(count),
+			[]string{
+				p.ProjectId,
+				k[0],
+				k[1],
+				k[2],
+				k[3],
+				k[4],
+			}...,
+		)
+	}
+
+	if !failed {
+		c.account.Inventory.Update(p.ProjectId, "compute", "backend_service", resources)
+	}
+}
+
+// locationOfGroup returns the zone or region of a backend's group (instance group or network endpoint group)
+// Group is the group's (self link) URL e.g. .../zones/{zone}/instanceGroups/{name}
+// Returns global for global (e.g. internet) network endpoint groups
+func locationOfGroup(group string) string {
+	parts := strings.Split(group, "/")
+	for i := 0; i < len(parts)-1; i++ {
+		if parts[i] == "zones" || parts[i] == "regions" {
+			return parts[i+1]
+		}
+	}
+	return "global"
+}
+
+// getHealth returns the number of a backend's instances (or endpoints) by health state
+// Global and regional backend services use different methods
+func (c *ComputeLoadBalancingCollector) getHealth(ctx context.Context, project, region, backendService, group string) (map[string]int, error) {
+	ref := &compute.ResourceGroupReference{
+		Group: group,
+	}
+
+	var resp *compute.BackendServiceGroupHealth
+	var err error
+	if region == "global" {
+		resp, err = c.computeService.BackendServices.GetHealth(project, backendService, ref).Context(ctx).Do()
+	} else {
+		resp, err = c.computeService.RegionBackendServices.GetHealth(project, region, backendService, ref).Context(ctx).Do()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	states := map[string]int{}
+	for _, status := range resp.HealthStatus {
+		states[status.HealthState]++
+	}
+	return states, nil
+}
+
+// collectURLMaps collects the project's URL maps
+func (c *ComputeLoadBalancingCollector) collectURLMaps(ctx context.Context, ch chan<- prometheus.Metric, p *cloudresourcemanager.Project) {
+	counts := map[string]int{}
+
+	rqst := c.computeService.UrlMaps.AggregatedList(p.ProjectId).MaxResults(500).ReturnPartialSuccess(true)
+	if err := rqst.Pages(ctx, func(page *compute.UrlMapsAggregatedList) error {
+		for scope, scoped := range page.Items {
+			counts[path.Base(scope)] += len(scoped.UrlMaps)
+		}
+		return nil
+	}); err != nil {
+		logError("ComputeLoadBalancingCollector", p.ProjectId, err)
+		return
+	}
+
+	for region, count := range counts {
+		if count == 0 {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(
+			c.URLMaps,
+			prometheus.GaugeValue,
+			float64(count),
+			[]string{
+				p.ProjectId,
+				region,
+			}...,
+		)
+	}
+}
+
+// collectTargetProxies collects the project's HTTP(S), SSL and TCP target proxies
+func (c *ComputeLoadBalancingCollector) collectTargetProxies(ctx context.Context, ch chan<- prometheus.Metric, p *cloudresourcemanager.Project) {
+	counts := map[[2]string]int{}
+
+	if err := c.computeService.TargetHttpProxies.AggregatedList(p.ProjectId).MaxResults(500).ReturnPartialSuccess(true).Pages(ctx, func(page *compute.TargetHttpProxyAggregatedList) error {
+		for scope, scoped := range page.Items {
+			counts[[2]string{path.Base(scope), "http"}] += len(scoped.TargetHttpProxies)
+		}
+		return nil
+	}); err != nil {
+		logError("ComputeLoadBalancingCollector", p.ProjectId, err)
+	}
+
+	if err := c.computeService.TargetHttpsProxies.AggregatedList(p.ProjectId).MaxResults(500).ReturnPartialSuccess(true).Pages(ctx, func(page *compute.TargetHttpsProxyAggregatedList) error {
+		for scope, scoped := range page.Items {
+			counts[[2]string{path.Base(scope), "https"}] += len(scoped.TargetHttpsProxies)
+		}
+		return nil
+	}); err != nil {
+		logError("ComputeLoadBalancingCollector", p.ProjectId, err)
+	}
+
+	if err := c.computeService.TargetTcpProxies.AggregatedList(p.ProjectId).MaxResults(500).ReturnPartialSuccess(true).Pages(ctx, func(page *compute.TargetTcpProxyAggregatedList) error {
+		for scope, scoped := range page.Items {
+			counts[[2]string{path.Base(scope), "tcp"}] += len(scoped.TargetTcpProxies)
+		}
+		return nil
+	}); err != nil {
+		logError("ComputeLoadBalancingCollector", p.ProjectId, err)
+	}
+
+	// SSL proxies are only global
+	if err := c.computeService.TargetSslProxies.List(p.ProjectId).MaxResults(500).Pages(ctx, func(page *compute.TargetSslProxyList) error {
+		counts[[2]string{"global", "ssl"}] += len(page.Items)
+		return nil
+	}); err != nil {
+		logError("ComputeLoadBalancingCollector", p.ProjectId, err)
+	}
+
+	for k, count := range counts {
+		if count == 0 {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(
+			c.TargetProxies,
+			prometheus.GaugeValue,
+			float64(count),
+			[]string{
+				p.ProjectId,
+				k[0],
+				k[1],
+			}...,
+		)
+	}
+}
+
+// Describe implements Prometheus' Collector interface and is used to describe metrics
+func (c *ComputeLoadBalancingCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.BackendServices
+	ch <- c.BackendHealth
+	ch <- c.URLMaps
+	ch <- c.TargetProxies
+}
@@ -0,0 +1,405 @@
+package collector
+
+import (
+	"context"
+	"log"
+	"net/netip"
+	"path"
+	"sync"
+
+	"github.com/DazWilkin/gcp-exporter/gcp"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"google.golang.org/api/cloudresourcemanager/v1"
+	"google.golang.org/api/compute/v1"
+)
+
+const (
+	// primaryRange is the range label of subnetworks' primary IPv4 ranges
+	primaryRange = "primary"
+	// primaryKind and secondaryKind are the kind labels of subnetworks' primary and secondary IPv4 ranges
+	// A secondary range may itself be named "primary"
+	primaryKind   = "primary"
+	secondaryKind = "secondary"
+	// reservedPrimaryIPs is the number of addresses reserved in every primary IPv4 range
+	// Secondary ranges do not have reserved addresses
+	reservedPrimaryIPs = 4
+)
+
+var (
+	_ prometheus.Collector = (*ComputeNetworksCollector)(nil)
+)
+
+// ComputeNetworksCollector represents VPC networks and subnetworks
+type ComputeNetworksCollector struct {
+	account        *gcp.Account
+	computeService *compute.Service
+
+	Networks         *prometheus.Desc
+	Subnetworks      *prometheus.Desc
+	SubnetIPCapacity *prometheus.Desc
+	SubnetIPUsed     *prometheus.Desc
+}
+
+// NewComputeNetworksCollector returns a new ComputeNetworksCollector
+func NewComputeNetworksCollector(account *gcp.Account) (*ComputeNetworksCollector, error) {
+	subsystem := "compute_engine"
+
+	ctx := context.Background()
+	computeService, err := compute.NewService(ctx)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	subnetLabels := []string{
+		"project",
+		"region",
+		"network",
+		"subnetwork",
+		"kind",
+		"range",
+	}
+
+	return &ComputeNetworksCollector{
+		account:        account,
+		computeService: computeService,
+
+		Networks: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "networks"),
+			"Number of VPC networks",
+			[]string{
+				"project",
+				"routing_mode",
+			},
+			nil,
+		),
+		Subnetworks: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "subnetworks"),
+			"Number of VPC subnetworks",
+			[]string{
+				"project",
+				"region",
+				"network",
+			},
+			nil,
+		),
+		SubnetIPCapacity: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "subnet_ip_capacity"),
+			"Number of usable IPv4 addresses in the subnetwork's range (range is primary or the secondary range's name)",
+			subnetLabels,
+			nil,
+		),
+		SubnetIPUsed: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "subnet_ip_used"),
+			"Number of IPv4 addresses allocated from the subnetwork's range to instances (including alias IP ranges), internal forwarding rules and reserved internal addresses",
+			subnetLabels,
+			nil,
+		),
+	}, nil
+}
+
+// allocations accumulates the IPv4 addresses allocated from subnetworks' ranges
+// Single addresses are deduplicated because reserved addresses may also be used by instances and forwarding rules
+type allocations struct {
+	addresses map[[3]string]map[string]bool
+	ranges    map[[3]string]int64
+}
+
+// addAddress is a method that records an address allocated from a subnetwork's range
+func (a *allocations) addAddress(subnetwork, kind, rangeName, address string) {
+	if subnetwork == "" || address == "" {
+		return
+	}
+	k := [3]string{subnetwork, kind, rangeName}
+	if _, ok := a.addresses[k]; !ok {
+		a.addresses[k] = map[string]bool{}
+	}
+	a.addresses[k][address] = true
+}
+
+// addRange is a method that records a CIDR range allocated from a subnetwork's range
+func (a *allocations) addRange(subnetwork, kind, rangeName, cidr string) {
+	if subnetwork == "" {
+		return
+	}
+	a.ranges[[3]string{subnetwork, kind, rangeName}] += cidrSize(cidr)
+}
+
+// used is a method that returns the number of addresses allocated from a subnetwork's range
+func (a *allocations) used(subnetwork, kind, rangeName string) int64 {
+	k := [3]string{subnetwork, kind, rangeName}
+	return int64(len(a.addresses[k])) + a.ranges[k]
+}
+
+// Collect implements Prometheus' Collector interface and is used to collect metrics
+func (c *ComputeNetworksCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx := context.Background()
+
+	// Enumerate all of the projects
+	var wg sync.WaitGroup
+	for _, p := range c.account.Projects {
+		wg.Add(1)
+		go func(p *cloudresourcemanager.Project) {
+			defer wg.Done()
+			log.Printf("[ComputeNetworksCollector] Project: %s", p.ProjectId)
+
+			c.collectNetworks(ctx, ch, p)
+
+			subnetworks, ok := c.listSubnetworks(ctx, p)
+			if !ok {
+				return
+			}
+
+			// Allocations are keyed by subnetwork self link
+			// Only the project's own resources are considered; allocations by Shared VPC service projects are not included
+			a := &allocations{
+				addresses: map[[3]string]map[string]bool{},
+				ranges:    map[[3]string]int64{},
+			}
+			// Usage is only reported if every allocation was listed; partial allocations would under-report usage
+			instancesFailed := c.listInstanceAllocations(ctx, p, a)
+			addressesFailed := c.listAddressAllocations(ctx, p, a)
+			forwardingRulesFailed := c.listForwardingRuleAllocations(ctx, p, a)
+			failed := instancesFailed || addressesFailed || forwardingRulesFailed
+
+			counts := map[[2]string]int{}
+			for _, subnetwork := range subnetworks {
+				region := path.Base(subnetwork.Region)
+				network := path.Base(subnetwork.Network)
+				counts[[2]string{region, network}]++
+
+				collect := func(kind, rangeName, cidr string, reserved int64) {
+					labels := []string{
+						p.ProjectId,
+						region,
+						network,
+						subnetwork.Name,
+						kind,
+						rangeName,
+					}
+					ch <- prometheus.MustNewConstMetric(
+						c.SubnetIPCapacity,
+						prometheus.GaugeValue,
+						float64(max(cidrSize(cidr)-reserved, 0)),
+						labels...,
+					)
+					if failed {
+						return
+					}
+					ch <- prometheus.MustNewConstMetric(
+						c.SubnetIPUsed,
+						prometheus.GaugeValue,
+						float64(a.used(subnetwork.SelfLink, kind, rangeName)),
+						labels...,
+					)
+				}
+
+				collect(primaryKind, primaryRange, subnetwork.IpCidrRange, reservedPrimaryIPs)
+				for _, secondary := range subnetwork.SecondaryIpRanges {
+					collect(secondaryKind, secondary.RangeName, secondary.IpCidrRange, 0)
+				}
+			}
+			for k, count := range counts {
+				ch <- prometheus.MustNewConstMetric(
+					c.Subnetworks,
+					prometheus.GaugeValue,
+					float64(count),
+					[]string{
+						p.ProjectId,
+						k[0],
+						k[1],
+					}...,
+				)
+			}
+		}(p)
+	}
+	wg.Wait()
+}
+
+// collectNetworks collects the project's VPC networks
+func (c *ComputeNetworksCollector) collectNetworks(ctx context.Context, ch chan<- prometheus.Metric, p *cloudresourcemanager.Project) {
+	counts := map[string]int{}
+	resources := []gcp.Resource{}
+
+	rqst := c.computeService.Networks.List(p.ProjectId).MaxResults(500)
+	if err := rqst.Pages(ctx, func(page *compute.NetworkList) error {
+		for _, network := range page.Items {
+			routingMode := ""
+			if network.RoutingConfig != nil {
+				routingMode = network.RoutingConfig.RoutingMode
+			}
+			counts[routingMode]++
+
+			resources = append(resources, gcp.Resource{
+				Project:  p.ProjectId,
+				Service:  "compute",
+				Type:     "network",
+				Location: "global",
+				Name:     network.Name,
+			})
+		}
+		return nil
+	}); err != nil {
+		logError("ComputeNetworksCollector", p.ProjectId, err)
+		return
+	}
+
+	for routingMode, count := range counts {
+		ch <- prometheus.MustNewConstMetric(
+			c.Networks,
+			prometheus.GaugeValue,
+			float64(count),
+			[]string{
+				p.ProjectId,
+				routingMode,
+			}...,
+		)
+	}
+
+	c.account.Inventory.Update(p.ProjectId, "compute", "network", resources)
+}
+
+// listSubnetworks returns the project's subnetworks keyed by self link
+// Returns false if the subnetworks could not be listed
+func (c *ComputeNetworksCollector) listSubnetworks(ctx context.Context, p *cloudresourcemanager.Project) (map[string]*compute.Subnetwork, bool) {
+	subnetworks := map[string]*compute.Subnetwork{}
+	resources := []gcp.Resource{}
+	// The inventory is not updated if any region is unreachable to avoid reporting its resources as deleted
+	failed := false
+
+	rqst := c.computeService.Subnetworks.AggregatedList(p.ProjectId).MaxResults(500).ReturnPartialSuccess(true)
+	if err := rqst.Pages(ctx, func(page *compute.SubnetworkAggregatedList) error {
+		if len(page.Unreachables) != 0 {
+			log.Printf("[ComputeNetworksCollector] Project: %s -- Subnetworks.AggregatedList unreachable: %v", p.ProjectId, page.Unreachables)
+			failed = true
+		}
+		for _, scoped := range page.Items {
+			for _, subnetwork := range scoped.Subnetworks {
+				subnetworks[subnetwork.SelfLink] = subnetwork
+
+				resources = append(resources, gcp.Resource{
+					Project:  p.ProjectId,
+					Service:  "compute",
+					Type:     "subnetwork",
+					Location: path.Base(subnetwork.Region),
+					Name:     subnetwork.Name,
+					State:    subnetwork.State,
+				})
+			}
+		}
+		return nil
+	}); err != nil {
+		logError("ComputeNetworksCollector", p.ProjectId, err)
+		return nil, false
+	}
+
+	if !failed {
+		c.account.Inventory.Update(p.ProjectId, "compute", "subnetwork", resources)
+	}
+
+	return subnetworks, true
+}
+
+// listInstanceAllocations records the addresses and alias IP ranges allocated to the project's instances
+// GKE (VPC-native) Pods are allocated alias IP ranges from the nodes' subnetwork's secondary ranges
+// Returns true if the allocations could not all be listed
+func (c *ComputeNetworksCollector) listInstanceAllocations(ctx context.Context, p *cloudresourcemanager.Project, a *allocations) bool {
+	failed := false
+	rqst := c.computeService.Instances.AggregatedList(p.ProjectId).MaxResults(500).ReturnPartialSuccess(true)
+	if err := rqst.Pages(ctx, func(page *compute.InstanceAggregatedList) error {
+		if len(page.Unreachables) != 0 {
+			log.Printf("[ComputeNetworksCollector] Project: %s -- Instances.AggregatedList unreachable: %v", p.ProjectId, page.Unreachables)
+			failed = true
+		}
+		for _, scoped := range page.Items {
+			for _, instance := range scoped.Instances {
+				for _, nic := range instance.NetworkInterfaces {
+					a.addAddress(nic.Subnetwork, primaryKind, primaryRange, nic.NetworkIP)
+					for _, alias := range nic.AliasIpRanges {
+						// Alias IP ranges without a range name are allocated from the primary range
+						kind, rangeName := secondaryKind, alias.SubnetworkRangeName
+						if rangeName == "" {
+							kind, rangeName = primaryKind, primaryRange
+						}
+						a.addRange(nic.Subnetwork, kind, rangeName, alias.IpCidrRange)
+					}
+				}
+			}
+		}
+		return nil
+	}); err != nil {
+		logError("ComputeNetworksCollector", p.ProjectId, err)
+		failed = true
+	}
+	return failed
+}
+
+// listAddressAllocations records the project's reserved internal addresses
+// Returns true if the allocations could not all be listed
+func (c *ComputeNetworksCollector) listAddressAllocations(ctx context.Context, p *cloudresourcemanager.Project, a *allocations) bool {
+	failed := false
+	rqst := c.computeService.Addresses.AggregatedList(p.ProjectId).MaxResults(500).ReturnPartialSuccess(true)
+	if err := rqst.Pages(ctx, func(page *compute.AddressAggregatedList) error {
+		if len(page.Unreachables) != 0 {
+			log.Printf("[ComputeNetworksCollector] Project: %s -- Addresses.AggregatedList unreachable: %v", p.ProjectId, page.Unreachables)
+			failed = true
+		}
+		for _, scoped := range page.Items {
+			for _, address := range scoped.Addresses {
+				if address.AddressType != "INTERNAL" {
+					continue
+				}
+				a.addAddress(address.Subnetwork, primaryKind, primaryRange, address.Address)
+			}
+		}
+		return nil
+	}); err != nil {
+		logError("ComputeNetworksCollector", p.ProjectId, err)
+		failed = true
+	}
+	return failed
+}
+
+// listForwardingRuleAllocations records the addresses of the project's internal forwarding rules
+// Returns true if the allocations could not all be listed
+func (c *ComputeNetworksCollector) listForwardingRuleAllocations(ctx context.Context, p *cloudresourcemanager.Project, a *allocations) bool {
+	failed := false
+	rqst := c.computeService.ForwardingRules.AggregatedList(p.ProjectId).MaxResults(500).ReturnPartialSuccess(true)
+	if err := rqst.Pages(ctx, func(page *compute.ForwardingRuleAggregatedList) error {
+		if len(page.Unreachables) != 0 {
+			log.Printf("[ComputeNetworksCollector] Project: %s -- ForwardingRules.AggregatedList unreachable: %v", p.ProjectId, page.Unreachables)
+			failed = true
+		}
+		for _, scoped := range page.Items {
+			for _, rule := range scoped.ForwardingRules {
+				// External forwarding rules do not have a subnetwork
+				a.addAddress(rule.Subnetwork, primaryKind, primaryRange, rule.IPAddress)
+			}
+		}
+		return nil
+	}); err != nil {
+		logError("ComputeNetworksCollector", p.ProjectId, err)
+		failed = true
+	}
+	return failed
+}
+
+// cidrSize returns the number of addresses in an IPv4 CIDR range (e.g. 10.0.0.0/24 ==> 256)
+// Returns 0 for IPv6 and invalid ranges
+func cidrSize(cidr string) int64 {
+	network, err := netip.ParsePrefix(cidr)
+	if err != nil || !network.Addr().Is4() {
+		return 0
+	}
+	ones := network.Bits()
+	return 1 << (32 - ones)
+}
+
+// Describe implements Prometheus' Collector interface and is used to describe metrics
+func (c *ComputeNetworksCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.Networks
+	ch <- c.Subnetworks
+	ch <- c.SubnetIPCapacity
+	ch <- c.SubnetIPUsed
+}
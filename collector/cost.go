@@ -0,0 +1,282 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path"
+	"sync"
+
+	"github.com/DazWilkin/gcp-exporter/gcp"
+	"github.com/DazWilkin/gcp-exporter/pricing"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"google.golang.org/api/cloudresourcemanager/v1"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/container/v1"
+	sqladmin "google.golang.org/api/sqladmin/v1"
+)
+
+var (
+	_ prometheus.Collector = (*CostCollector)(nil)
+)
+
+// CostCollector represents the estimated running cost of resources
+// Costs are estimated from a price table and are not billing data
+type CostCollector struct {
+	account          *gcp.Account
+	computeService   *compute.Service
+	containerService *container.Service
+	sqladminService  *sqladmin.Service
+
+	prices *pricing.Table
+
+	HourlyCost *prometheus.Desc
+}
+
+// NewCostCollector returns a new CostCollector
+func NewCostCollector(account *gcp.Account, prices *pricing.Table) (*CostCollector, error) {
+	subsystem := "estimated"
+
+	ctx := context.Background()
+	computeService, err := compute.NewService(ctx)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	containerService, err := container.NewService(ctx)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	sqladminService, err := sqladmin.NewService(ctx)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return &CostCollector{
+		account:          account,
+		computeService:   computeService,
+		containerService: containerService,
+		sqladminService:  sqladminService,
+
+		prices: prices,
+
+		HourlyCost: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "hourly_cost_usd"),
+			"Estimated hourly cost (USD) of running resources",
+			withResourceLabels(account,
+				"project",
+				"service",
+				"location",
+			),
+			nil,
+		),
+	}, nil
+}
+
+// costs accumulates estimated hourly costs by service, location (region) and allowed resource labels
+type costs struct {
+	mu     sync.Mutex
+	m      map[string]float64
+	labels *gcp.LabelAllowlist
+}
+
+// add is a method that adds a resource's hourly cost
+func (x *costs) add(service, location string, labels map[string]string, cost float64) {
+	x.mu.Lock()
+	x.m[groupKey(append([]string{service, location}, x.labels.Values(labels)...)...)] += cost
+	x.mu.Unlock()
+}
+
+// Collect implements Prometheus' Collector interface and is used to collect metrics
+func (c *CostCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx := context.Background()
+
+	// Enumerate all of the projects
+	var wg sync.WaitGroup
+	for _, p := range c.account.Projects {
+		wg.Add(1)
+		go func(p *cloudresourcemanager.Project) {
+			defer wg.Done()
+			log.Printf("[CostCollector] Project: %s", p.ProjectId)
+
+			x := &costs{
+				m:      map[string]float64{},
+				labels: c.account.Labels,
+			}
+
+			// WaitGroup is used for the project's services
+			var pwg sync.WaitGroup
+			for _, collect := range []func(context.Context, *cloudresourcemanager.Project, *costs){
+				c.collectInstances,
+				c.collectDisks,
+				c.collectClusters,
+				c.collectSQLInstances,
+			} {
+				pwg.Add(1)
+				go func() {
+					defer pwg.Done()
+					collect(ctx, p, x)
+				}()
+			}
+			pwg.Wait()
+
+			for k, cost := range x.m {
+				ch <- prometheus.MustNewConstMetric(
+					c.HourlyCost,
+					prometheus.GaugeValue,
+					cost,
+					append([]string{p.ProjectId}, groupValues(k)...)...,
+				)
+			}
+		}(p)
+	}
+	wg.Wait()
+}
+
+// collectInstances estimates the cost of running Compute Engine instances
+// Instances that are GKE nodes are attributed to GKE
+func (c *CostCollector) collectInstances(ctx context.Context, p *cloudresourcemanager.Project, x *costs) {
+	rqst := c.computeService.Instances.AggregatedList(p.ProjectId).ReturnPartialSuccess(true)
+	if err := rqst.Pages(ctx, func(page *compute.InstanceAggregatedList) error {
+		for _, scoped := range page.Items {
+			for _, instance := range scoped.Instances {
+				// Stopped instances only incur the cost of their disks
+				if instance.Status != "RUNNING" {
+					continue
+				}
+
+				machineType := path.Base(instance.MachineType)
+				region := regionOf(path.Base(instance.Zone))
+
+				// Spot and preemptible instances are priced at Spot prices (rather than on-demand prices)
+				if instance.Scheduling != nil && (instance.Scheduling.ProvisioningModel == "SPOT" || instance.Scheduling.Preemptible) {
+					cost, ok := c.prices.SpotMachineType(machineType, region)
+					if !ok {
+						log.Printf("[CostCollector] No Spot price for machine type: %s (%s)", machineType, region)
+						continue
+					}
+
+					x.add(serviceOf(instance.Labels), region, instance.Labels, cost)
+					continue
+				}
+
+				cost, ok := c.prices.MachineType(machineType, region)
+				if !ok {
+					log.Printf("[CostCollector] No price for machine type: %s (%s)", machineType, region)
+					continue
+				}
+
+				x.add(serviceOf(instance.Labels), region, instance.Labels, cost)
+			}
+		}
+		return nil
+	}); err != nil {
+		logError("CostCollector", p.ProjectId, err)
+	}
+}
+
+// collectDisks estimates the cost of Compute Engine persistent disks
+func (c *CostCollector) collectDisks(ctx context.Context, p *cloudresourcemanager.Project, x *costs) {
+	rqst := c.computeService.Disks.AggregatedList(p.ProjectId).ReturnPartialSuccess(true)
+	if err := rqst.Pages(ctx, func(page *compute.DiskAggregatedList) error {
+		for _, scoped := range page.Items {
+			for _, disk := range scoped.Disks {
+				diskType := path.Base(disk.Type)
+
+				// Disks are either zonal or regional
+				region := path.Base(disk.Region)
+				if disk.Zone != "" {
+					region = regionOf(path.Base(disk.Zone))
+				}
+
+				cost, ok := c.prices.Disk(diskType, region, disk.SizeGb)
+				if !ok {
+					log.Printf("[CostCollector] No price for disk type: %s (%s)", diskType, region)
+					continue
+				}
+
+				x.add(serviceOf(disk.Labels), region, disk.Labels, cost)
+			}
+		}
+		return nil
+	}); err != nil {
+		logError("CostCollector", p.ProjectId, err)
+	}
+}
+
+// collectClusters estimates the cost of the GKE cluster management fee
+// The cost of the clusters' nodes is estimated by collectInstances
+func (c *CostCollector) collectClusters(ctx context.Context, p *cloudresourcemanager.Project, x *costs) {
+	parent := fmt.Sprintf("projects/%s/locations/-", p.ProjectId)
+	resp, err := c.containerService.Projects.Locations.Clusters.List(parent).Context(ctx).Do()
+	if err != nil {
+		logError("CostCollector", p.ProjectId, err)
+		return
+	}
+
+	for _, cluster := range resp.Clusters {
+		region := regionOf(cluster.Location)
+
+		cost, ok := c.prices.GKECluster(region)
+		if !ok {
+			log.Printf("[CostCollector] No price for GKE cluster (%s)", region)
+			continue
+		}
+
+		x.add("gke", region, cluster.ResourceLabels, cost)
+	}
+}
+
+// collectSQLInstances estimates the cost of Cloud SQL instances' tiers and storage
+func (c *CostCollector) collectSQLInstances(ctx context.Context, p *cloudresourcemanager.Project, x *costs) {
+	rqst := c.sqladminService.Instances.List(p.ProjectId)
+	if err := rqst.Pages(ctx, func(page *sqladmin.InstancesListResponse) error {
+		for _, instance := range page.Items {
+			if instance.Settings == nil {
+				continue
+			}
+
+			region := instance.Region
+
+			// Instances that are stopped only incur the cost of their storage
+			if instance.State == "RUNNABLE" && instance.Settings.ActivationPolicy != "NEVER" {
+				cost, ok := c.prices.SQLTier(instance.Settings.Tier, region)
+				if !ok {
+					log.Printf("[CostCollector] No price for Cloud SQL tier: %s (%s)", instance.Settings.Tier, region)
+				} else {
+					x.add("sql", region, instance.Settings.UserLabels, cost)
+				}
+			}
+
+			// Cloud SQL data disk types are PD_SSD|PD_HDD
+			// Cloud SQL storage has its own prices (not Compute Engine disk prices)
+			diskType := instance.Settings.DataDiskType
+			regional := instance.Settings.AvailabilityType == "REGIONAL"
+			if cost, ok := c.prices.SQLDisk(diskType, region, instance.Settings.DataDiskSizeGb, regional); ok {
+				x.add("sql", region, instance.Settings.UserLabels, cost)
+			} else {
+				log.Printf("[CostCollector] No price for Cloud SQL storage: %s (%s)", diskType, region)
+			}
+		}
+		return nil
+	}); err != nil {
+		logError("CostCollector", p.ProjectId, err)
+	}
+}
+
+// serviceOf returns the service to which a Compute Engine resource is attributed
+// GKE nodes (and their disks) are labeled goog-gke-node
+func serviceOf(labels map[string]string) string {
+	if _, ok := labels["goog-gke-node"]; ok {
+		return "gke"
+	}
+	return "compute"
+}
+
+// Describe implements Prometheus' Collector interface and is used to describe metrics
+func (c *CostCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.HourlyCost
+}
@@ -68,6 +68,7 @@ func (c *EndpointsCollector) Collect(ch chan<- prometheus.Metric) {
 			rqst := c.servicemanagementService.Services.List().ProducerProjectId(p.ProjectId)
 
 			services := 0
+			resources := []gcp.Resource{}
 
 			for {
 				resp, err := rqst.Do()
@@ -88,6 +89,16 @@ func (c *EndpointsCollector) Collect(ch chan<- prometheus.Metric) {
 
 				services += len(resp.Services)
 
+				for _, service := range resp.Services {
+					resources = append(resources, gcp.Resource{
+						Project:  p.ProjectId,
+						Service:  "endpoints",
+						Type:     "service",
+						Location: "global",
+						Name:     service.ServiceName,
+					})
+				}
+
 				// If there are no more pages, we're done
 				if resp.NextPageToken == "" {
 					break
@@ -97,6 +108,8 @@ func (c *EndpointsCollector) Collect(ch chan<- prometheus.Metric) {
 				rqst = rqst.PageToken(resp.NextPageToken)
 			}
 
+			c.account.Inventory.Update(p.ProjectId, "endpoints", "service", resources)
+
 			ch <- prometheus.MustNewConstMetric(
 				c.Services,
 				prometheus.GaugeValue,
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"path"
 	"sync"
 
 	"github.com/DazWilkin/gcp-exporter/gcp"
@@ -40,25 +41,25 @@ func NewEventarcCollector(account *gcp.Account) (*EventarcCollector, error) {
 		Channels: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, subsystem, "channels"),
 			"1 if the channel exists",
-			[]string{
+			withResourceLabels(account,
 				"project",
 				"name",
 				"provider",
 				"pubsubtopic",
 				"state",
-			},
+			),
 			nil,
 		),
 		Triggers: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, subsystem, "triggers"),
 			"1 if the trigger exists",
-			[]string{
+			withResourceLabels(account,
 				"project",
 				"name",
 				"channel",
 				"contenttype",
 				"destination",
-			},
+			),
 			nil,
 		),
 	}, nil
@@ -66,6 +67,8 @@ func NewEventarcCollector(account *gcp.Account) (*EventarcCollector, error) {
 
 // Collect implements Prometheus' Collector interface and is used to collect metrics
 func (c *EventarcCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx := context.Background()
+
 	// Enumerate all of the projects
 	var wg sync.WaitGroup
 	for _, p := range c.account.Projects {
@@ -78,8 +81,41 @@ func (c *EventarcCollector) Collect(ch chan<- prometheus.Metric) {
 			defer wg.Done()
 
 			rqst := c.eventarcService.Projects.Locations.Channels.List(parent)
-			resp, err := rqst.Do()
-			if err != nil {
+			resources := []gcp.Resource{}
+			// Channels in unreachable locations would otherwise be recorded as deleted
+			unreachable := false
+			if err := rqst.Pages(ctx, func(page *eventarc.ListChannelsResponse) error {
+				if len(page.Unreachable) != 0 {
+					log.Printf("[EventarcCollector] Project: %s -- Channels.List unreachable: %v", p.ProjectId, page.Unreachable)
+					unreachable = true
+				}
+				for _, channel := range page.Channels {
+					log.Printf("[EventarcCollector] channel: %s", channel.Name)
+					resources = append(resources, gcp.Resource{
+						Project:  p.ProjectId,
+						Service:  "eventarc",
+						Type:     "channel",
+						Location: locationOf(channel.Name),
+						Name:     path.Base(channel.Name),
+						State:    channel.State,
+						Labels:   channel.Labels,
+					})
+
+					ch <- prometheus.MustNewConstMetric(
+						c.Channels,
+						prometheus.CounterValue,
+						1.0,
+						append([]string{
+							p.ProjectId,
+							channel.Name,
+							channel.Provider,
+							channel.PubsubTopic,
+							channel.State,
+						}, c.account.Labels.Values(channel.Labels)...)...,
+					)
+				}
+				return nil
+			}); err != nil {
 				if e, ok := err.(*googleapi.Error); ok {
 					if e.Code == http.StatusForbidden {
 						// Probably (!) Eventarc API has not enabled in this Project
@@ -94,20 +130,8 @@ func (c *EventarcCollector) Collect(ch chan<- prometheus.Metric) {
 				return
 			}
 
-			for _, channel := range resp.Channels {
-				log.Printf("[EventarcCollector] channel: %s", channel.Name)
-				ch <- prometheus.MustNewConstMetric(
-					c.Channels,
-					prometheus.CounterValue,
-					1.0,
-					[]string{
-						p.ProjectId,
-						channel.Name,
-						channel.Provider,
-						channel.PubsubTopic,
-						channel.State,
-					}...,
-				)
+			if !unreachable {
+				c.account.Inventory.Update(p.ProjectId, "eventarc", "channel", resources)
 			}
 		}()
 
@@ -117,8 +141,54 @@ func (c *EventarcCollector) Collect(ch chan<- prometheus.Metric) {
 			defer wg.Done()
 
 			rqst := c.eventarcService.Projects.Locations.Triggers.List(parent)
-			resp, err := rqst.Do()
-			if err != nil {
+			resources := []gcp.Resource{}
+			// Triggers in unreachable locations would otherwise be recorded as deleted
+			unreachable := false
+			if err := rqst.Pages(ctx, func(page *eventarc.ListTriggersResponse) error {
+				if len(page.Unreachable) != 0 {
+					log.Printf("[EventarcCollector] Project: %s -- Triggers.List unreachable: %v", p.ProjectId, page.Unreachable)
+					unreachable = true
+				}
+				for _, trigger := range page.Triggers {
+					log.Printf("[EventarcCollector] trigger: %s", trigger.Name)
+					resources = append(resources, gcp.Resource{
+						Project:  p.ProjectId,
+						Service:  "eventarc",
+						Type:     "trigger",
+						Location: locationOf(trigger.Name),
+						Name:     path.Base(trigger.Name),
+						Labels:   trigger.Labels,
+					})
+
+					ch <- prometheus.MustNewConstMetric(
+						c.Triggers,
+						prometheus.CounterValue,
+						1.0,
+						append([]string{
+							p.ProjectId,
+							trigger.Name,
+							trigger.Channel,
+							trigger.EventDataContentType,
+							func(d *eventarc.Destination) string {
+								if d.CloudFunction != "" {
+									return "cloudfunction"
+								}
+								if d.CloudRun != nil {
+									return "cloudrun"
+								}
+								if d.Gke != nil {
+									return "gke"
+								}
+								if d.Workflow != "" {
+									return "workflow"
+								}
+								return ""
+							}(trigger.Destination),
+						}, c.account.Labels.Values(trigger.Labels)...)...,
+					)
+				}
+				return nil
+			}); err != nil {
 				if e, ok := err.(*googleapi.Error); ok {
 					if e.Code == http.StatusForbidden {
 						// Probably (!) Eventarc API has not enabled in this Project
@@ -133,34 +203,8 @@ func (c *EventarcCollector) Collect(ch chan<- prometheus.Metric) {
 				return
 			}
 
-			for _, trigger := range resp.Triggers {
-				log.Printf("[EventarcCollector] trigger: %s", trigger.Name)
-				ch <- prometheus.MustNewConstMetric(
-					c.Triggers,
-					prometheus.CounterValue,
-					1.0,
-					[]string{
-						p.ProjectId,
-						trigger.Name,
-						trigger.Channel,
-						trigger.EventDataContentType,
-						func(d *eventarc.Destination) string {
-							if d.CloudFunction != "" {
-								return "cloudfunction"
-							}
-							if d.CloudRun != nil {
-								return "cloudrun"
-							}
-							if d.Gke != nil {
-								return "gke"
-							}
-							if d.Workflow != "" {
-								return "workflow"
-							}
-							return ""
-						}(trigger.Destination),
-					}...,
-				)
+			if !unreachable {
+				c.account.Inventory.Update(p.ProjectId, "eventarc", "trigger", resources)
 			}
 		}()
 	}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"path"
 	"strings"
 	"sync"
 
@@ -12,22 +13,38 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"google.golang.org/api/cloudfunctions/v1"
+	cloudfunctionsv2 "google.golang.org/api/cloudfunctions/v2"
 	"google.golang.org/api/cloudresourcemanager/v1"
 	"google.golang.org/api/googleapi"
+	"google.golang.org/api/run/v2"
 )
 
 var (
 	_ prometheus.Collector = (*FunctionsCollector)(nil)
 )
 
+var (
+	// functionInvokerRoles are the IAM roles that permit invoking functions
+	// 1st gen functions are invoked with roles/cloudfunctions.invoker
+	// 2nd gen functions are invoked through their Cloud Run service and so with Cloud Run's invoker roles
+	functionInvokerRoles = map[string]bool{
+		"roles/cloudfunctions.invoker": true,
+		"roles/run.invoker":            true,
+		"roles/run.servicesInvoker":    true,
+	}
+)
+
 // FunctionsCollector represents Cloud Functions
 type FunctionsCollector struct {
-	account               *gcp.Account
-	cloudfunctionsService *cloudfunctions.Service
+	account                 *gcp.Account
+	cloudfunctionsService   *cloudfunctions.Service
+	cloudfunctionsV2Service *cloudfunctionsv2.Service
+	cloudrunService         *run.Service
 
-	Functions *prometheus.Desc
-	Locations *prometheus.Desc
-	Runtimes  *prometheus.Desc
+	Functions      *prometheus.Desc
+	Locations      *prometheus.Desc
+	Runtimes       *prometheus.Desc
+	FunctionPublic *prometheus.Desc
 }
 
 // NewFunctionsCollector returns a new FunctionsCollector
@@ -41,34 +58,60 @@ func NewFunctionsCollector(account *gcp.Account) (*FunctionsCollector, error) {
 		return nil, err
 	}
 
+	// 2nd gen functions are only listed by the v2 API
+	cloudfunctionsV2Service, err := cloudfunctionsv2.NewService(ctx)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	// 2nd gen functions' IAM policies are those of their Cloud Run services
+	cloudrunService, err := run.NewService(ctx)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
 	return &FunctionsCollector{
-		account:               account,
-		cloudfunctionsService: cloudfunctionsService,
+		account:                 account,
+		cloudfunctionsService:   cloudfunctionsService,
+		cloudfunctionsV2Service: cloudfunctionsV2Service,
+		cloudrunService:         cloudrunService,
 
 		Functions: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, subsystem, "functions"),
 			"Number of Cloud Functions",
-			[]string{
+			withResourceLabels(account,
 				"project",
-			},
+			),
 			nil,
 		),
 		Locations: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, subsystem, "locations"),
 			"Number of Functions by Location",
-			[]string{
+			withResourceLabels(account,
 				"project",
 				"location",
-			},
+			),
 			nil,
 		),
 		Runtimes: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, subsystem, "runtimes"),
 			"Number of Functions by Runtime",
-			[]string{
+			withResourceLabels(account,
 				"project",
 				"runtime",
-			},
+			),
+			nil,
+		),
+		FunctionPublic: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "function_public"),
+			"1 if allUsers or allAuthenticatedUsers may invoke the function, 0 otherwise",
+			withResourceLabels(account,
+				"project",
+				"location",
+				"function",
+			),
 			nil,
 		),
 	}, nil
@@ -82,13 +125,31 @@ func (c *FunctionsCollector) Collect(ch chan<- prometheus.Metric) {
 		wg.Add(1)
 		go func(p *cloudresourcemanager.Project) {
 			defer wg.Done()
+
+			// WaitGroup is used for the functions' IAM policies
+			// Deferred calls run in reverse order so the policies are collected before the project is done
+			var fwg sync.WaitGroup
+			defer fwg.Wait()
+
 			log.Printf("[CloudFunctionsCollector] Project: %s", p.ProjectId)
 			parent := fmt.Sprintf("projects/%s/locations/-", p.ProjectId)
+
+			fwg.Add(1)
+			go func() {
+				defer fwg.Done()
+				c.collectGen2Public(ch, p.ProjectId, parent)
+			}()
+
 			rqst := c.cloudfunctionsService.Projects.Locations.Functions.List(parent)
 
-			functions := 0
+			// Counts are grouped by the allowed resource labels' values
+			// Projects without functions report 0 (with placeholder values)
+			functions := map[string]int{
+				groupKey(append([]string{p.ProjectId}, c.account.Labels.Values(nil)...)...): 0,
+			}
 			locations := make(map[string]int)
 			runtimes := make(map[string]int)
+			resources := []gcp.Resource{}
 
 			// Do request at least once
 			for {
@@ -108,8 +169,6 @@ func (c *FunctionsCollector) Collect(ch chan<- prometheus.Metric) {
 					return
 				}
 
-				functions += len(resp.Functions)
-
 				// https://cloud.google.com/functions/docs/reference/rest/v1/projects.locations.functions#CloudFunction
 				for _, function := range resp.Functions {
 					// Name == projects/*/locations/*/functions/*
@@ -118,13 +177,33 @@ func (c *FunctionsCollector) Collect(ch chan<- prometheus.Metric) {
 					// 0="projects",1="{project}",2="locations",3="{location}",4="functions",5="{function}"
 					if len(parts) != 6 {
 						log.Printf("[CloudFunctionsCollector] Unable to parse function name: %s", function.Name)
+						continue
 					}
+					resourceLabels := c.account.Labels.Values(function.Labels)
+					functions[groupKey(append([]string{p.ProjectId}, resourceLabels...)...)]++
+
 					// Increment locations count by this function's location
-					locations[parts[3]]++
+					locations[groupKey(append([]string{p.ProjectId, parts[3]}, resourceLabels...)...)]++
 
 					log.Printf("[CloudFunctionsCollector] runtime: %s", function.Runtime)
 					// Increment runtimes count by this function's runtime
-					runtimes[function.Runtime]++
+					runtimes[groupKey(append([]string{p.ProjectId, function.Runtime}, resourceLabels...)...)]++
+
+					resources = append(resources, gcp.Resource{
+						Project:  p.ProjectId,
+						Service:  "functions",
+						Type:     "function",
+						Location: parts[3],
+						Name:     parts[5],
+						State:    function.Status,
+						Labels:   function.Labels,
+					})
+
+					fwg.Add(1)
+					go func(name string, resourceLabels []string) {
+						defer fwg.Done()
+						c.collectPublic(ch, p.ProjectId, name, resourceLabels)
+					}(function.Name, resourceLabels)
 				}
 
 				// If there are no more pages, we're done
@@ -136,42 +215,41 @@ func (c *FunctionsCollector) Collect(ch chan<- prometheus.Metric) {
 				rqst = rqst.PageToken(resp.NextPageToken)
 			}
 
+			c.account.Inventory.Update(p.ProjectId, "functions", "function", resources)
+
 			// Now we know the number of Functions
 			// Because this count is by project, include project labels to avoid duplication
 			// Can always total by location across projects
 			// gcp_cloudfunctions_locations{location="us-central1",project="gcp"} 1
 			// gcp_cloudfunctions_locations{location="us-central1",project="yyy"} 1
-			ch <- prometheus.MustNewConstMetric(
-				c.Functions,
-				prometheus.GaugeValue,
-				float64(functions),
-				[]string{
-					p.ProjectId,
-				}...,
-			)
-			for location, count := range locations {
+			for k, count := range functions {
+				if count == 0 && len(resources) != 0 {
+					continue
+				}
+				ch <- prometheus.MustNewConstMetric(
+					c.Functions,
+					prometheus.GaugeValue,
+					float64(count),
+					groupValues(k)...,
+				)
+			}
+			for k, count := range locations {
 				ch <- prometheus.MustNewConstMetric(
 					c.Locations,
 					prometheus.GaugeValue,
 					float64(count),
-					[]string{
-						p.ProjectId,
-						location,
-					}...,
+					groupValues(k)...,
 				)
 			}
 			// Can always total by runtime across projects
 			// gcp_cloudfunctions_runtimes{project="gcp",runtime="go113"} 1
 			// gcp_cloudfunctions_runtimes{project="yyy",runtime="go113"} 1
-			for runtime, count := range runtimes {
+			for k, count := range runtimes {
 				ch <- prometheus.MustNewConstMetric(
 					c.Runtimes,
 					prometheus.GaugeValue,
 					float64(count),
-					[]string{
-						p.ProjectId,
-						runtime,
-					}...,
+					groupValues(k)...,
 				)
 			}
 		}(p)
@@ -179,7 +257,80 @@ func (c *FunctionsCollector) Collect(ch chan<- prometheus.Metric) {
 	wg.Wait()
 }
 
+// collectPublic collects whether a function's IAM policy permits anyone to invoke it
+// Name == projects/*/locations/*/functions/*
+func (c *FunctionsCollector) collectPublic(ch chan<- prometheus.Metric, project, name string, resourceLabels []string) {
+	policy, err := c.cloudfunctionsService.Projects.Locations.Functions.GetIamPolicy(name).Do()
+	if err != nil {
+		logError("CloudFunctionsCollector", project, err)
+		return
+	}
+
+	public := 0.0
+	for _, binding := range policy.Bindings {
+		if functionInvokerRoles[binding.Role] && hasPublicMember(binding.Members) {
+			public = 1.0
+			break
+		}
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		c.FunctionPublic,
+		prometheus.GaugeValue,
+		public,
+		append([]string{
+			project,
+			locationOf(name),
+			path.Base(name),
+		}, resourceLabels...)...,
+	)
+}
+
+// collectGen2Public collects whether 2nd gen functions' Cloud Run services' IAM policies permit anyone to invoke them
+// 2nd gen functions are not listed by the v1 API
+func (c *FunctionsCollector) collectGen2Public(ch chan<- prometheus.Metric, project, parent string) {
+	rqst := c.cloudfunctionsV2Service.Projects.Locations.Functions.List(parent)
+	if err := rqst.Pages(context.Background(), func(page *cloudfunctionsv2.ListFunctionsResponse) error {
+		for _, function := range page.Functions {
+			if function.Environment != "GEN_2" || function.ServiceConfig == nil || function.ServiceConfig.Service == "" {
+				continue
+			}
+
+			policy, err := c.cloudrunService.Projects.Locations.Services.GetIamPolicy(function.ServiceConfig.Service).Do()
+			if err != nil {
+				logError("CloudFunctionsCollector", project, err)
+				continue
+			}
+
+			public := 0.0
+			for _, binding := range policy.Bindings {
+				if functionInvokerRoles[binding.Role] && hasPublicMember(binding.Members) {
+					public = 1.0
+					break
+				}
+			}
+
+			ch <- prometheus.MustNewConstMetric(
+				c.FunctionPublic,
+				prometheus.GaugeValue,
+				public,
+				append([]string{
+					project,
+					locationOf(function.Name),
+					path.Base(function.Name),
+				}, c.account.Labels.Values(function.Labels)...)...,
+			)
+		}
+		return nil
+	}); err != nil {
+		logError("CloudFunctionsCollector", project, err)
+	}
+}
+
 // Describe implements Prometheus' Collector interface and is used to describe metrics
 func (c *FunctionsCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.Functions
+	ch <- c.Locations
+	ch <- c.Runtimes
+	ch <- c.FunctionPublic
 }
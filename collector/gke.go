@@ -49,25 +49,25 @@ func NewGKECollector(account *gcp.Account, enableExtendedMetrics bool) (*GKEColl
 		Up: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, subsystem, "up"),
 			"1 if the cluster is running, 0 otherwise",
-			labelKeys, nil,
+			withResourceLabels(account, labelKeys...), nil,
 		),
 		Info: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, subsystem, "info"),
 			"Cluster control plane information. 1 if the cluster is running, 0 otherwise",
-			append(labelKeys, "id", "mode", "endpoint", "network", "subnetwork",
-				"initial_cluster_version", "node_pools_count"),
+			withResourceLabels(account, append(labelKeys, "id", "mode", "endpoint", "network", "subnetwork",
+				"initial_cluster_version", "node_pools_count")...),
 			nil,
 		),
 		Nodes: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, subsystem, "nodes"),
 			"Number of nodes currently in the cluster",
-			labelKeys, nil,
+			withResourceLabels(account, labelKeys...), nil,
 		),
 		NodePoolsInfo: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, subsystem, "node_pools_info"),
 			"Cluster Node Pools Information. 1 if the Node Pool is running, 0 otherwise",
-			append(labelKeys, "etag", "cluster_id", "autoscaling", "disk_size_gb",
-				"disk_type", "image_type", "machine_type", "locations", "spot", "preemptible"),
+			withResourceLabels(account, append(labelKeys, "etag", "cluster_id", "autoscaling", "disk_size_gb",
+				"disk_type", "image_type", "machine_type", "locations", "spot", "preemptible")...),
 			nil,
 		),
 	}, nil
@@ -103,9 +103,27 @@ func (c *GKECollector) collectProjectMetrics(ctx context.Context, containerServi
 		return
 	}
 
+	// Clusters in unreachable locations would otherwise be recorded as deleted
+	if len(resp.MissingZones) != 0 {
+		log.Printf("[GKECollector] Project: %s -- Clusters.List missing zones: %v", p.ProjectId, resp.MissingZones)
+	}
+
+	resources := []gcp.Resource{}
 	for _, cluster := range resp.Clusters {
+		resources = append(resources, gcp.Resource{
+			Project:  p.ProjectId,
+			Service:  "gke",
+			Type:     "cluster",
+			Location: cluster.Location,
+			Name:     cluster.Name,
+			State:    cluster.Status,
+			Labels:   cluster.ResourceLabels,
+		})
 		c.collectClusterMetrics(p, cluster, ch)
 	}
+	if len(resp.MissingZones) == 0 {
+		c.account.Inventory.Update(p.ProjectId, "gke", "cluster", resources)
+	}
 }
 
 func (c *GKECollector) collectClusterMetrics(p *cloudresourcemanager.Project, cluster *container.Cluster,
@@ -118,11 +136,13 @@ func (c *GKECollector) collectClusterMetrics(p *cloudresourcemanager.Project, cl
 		clusterStatus = 1.0
 	}
 
+	resourceLabels := c.account.Labels.Values(cluster.ResourceLabels)
+
 	ch <- prometheus.MustNewConstMetric(c.Up, prometheus.GaugeValue, clusterStatus,
-		p.ProjectId, cluster.Name, cluster.Location, cluster.CurrentMasterVersion)
+		append([]string{p.ProjectId, cluster.Name, cluster.Location, cluster.CurrentMasterVersion}, resourceLabels...)...)
 
 	ch <- prometheus.MustNewConstMetric(c.Nodes, prometheus.GaugeValue, float64(cluster.CurrentNodeCount),
-		p.ProjectId, cluster.Name, cluster.Location, cluster.CurrentNodeVersion)
+		append([]string{p.ProjectId, cluster.Name, cluster.Location, cluster.CurrentNodeVersion}, resourceLabels...)...)
 
 	if c.enableExtendedMetrics {
 		c.collectExtendedMetrics(p, cluster, ch, clusterStatus)
@@ -143,10 +163,13 @@ func (c *GKECollector) collectExtendedMetrics(p *cloudresourcemanager.Project, c
 		clusterMode = "Autopilot"
 	}
 
+	// Node pools are labeled with their cluster's resource labels
+	resourceLabels := c.account.Labels.Values(cluster.ResourceLabels)
+
 	ch <- prometheus.MustNewConstMetric(c.Info, prometheus.GaugeValue, clusterStatus,
-		p.ProjectId, cluster.Name, cluster.Location, cluster.CurrentMasterVersion,
-		cluster.Id, clusterMode, cluster.Endpoint, cluster.Network, cluster.Subnetwork,
-		cluster.InitialClusterVersion, nodePoolsSize)
+		append([]string{p.ProjectId, cluster.Name, cluster.Location, cluster.CurrentMasterVersion,
+			cluster.Id, clusterMode, cluster.Endpoint, cluster.Network, cluster.Subnetwork,
+			cluster.InitialClusterVersion, nodePoolsSize}, resourceLabels...)...)
 
 	for _, nodePool := range cluster.NodePools {
 		nodePoolStatus := 0.0
@@ -157,13 +180,13 @@ func (c *GKECollector) collectExtendedMetrics(p *cloudresourcemanager.Project, c
 		boolToString := func(b bool) string { return strconv.FormatBool(b) }
 
 		ch <- prometheus.MustNewConstMetric(c.NodePoolsInfo, prometheus.GaugeValue, nodePoolStatus,
-			p.ProjectId, nodePool.Name, cluster.Location, nodePool.Version, nodePool.Etag, cluster.Id,
-			boolToString(nodePool.Autoscaling.Enabled),
-			strconv.FormatInt(nodePool.Config.DiskSizeGb, 10), nodePool.Config.DiskType,
-			nodePool.Config.ImageType, nodePool.Config.MachineType,
-			strings.Join(nodePool.Locations, ","),
-			boolToString(nodePool.Config.Spot),
-			boolToString(nodePool.Config.Preemptible))
+			append([]string{p.ProjectId, nodePool.Name, cluster.Location, nodePool.Version, nodePool.Etag, cluster.Id,
+				boolToString(nodePool.Autoscaling.Enabled),
+				strconv.FormatInt(nodePool.Config.DiskSizeGb, 10), nodePool.Config.DiskType,
+				nodePool.Config.ImageType, nodePool.Config.MachineType,
+				strings.Join(nodePool.Locations, ","),
+				boolToString(nodePool.Config.Spot),
+				boolToString(nodePool.Config.Preemptible)}, resourceLabels...)...)
 	}
 }
 
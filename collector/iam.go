@@ -83,8 +83,13 @@ func (c *IAMCollector) Collect(ch chan<- prometheus.Metric) {
 			defer wg.Done()
 			log.Printf("IAMCollector:go] Project: %s", p.ProjectId)
 			parent := fmt.Sprintf("projects/%s", p.ProjectId)
-			resp, err := c.iamService.Projects.ServiceAccounts.List(parent).Context(ctx).Do()
-			if err != nil {
+			// Service Accounts are paginated; the inventory is updated only once every page has been read
+			accounts := []*iam.ServiceAccount{}
+			rqst := c.iamService.Projects.ServiceAccounts.List(parent)
+			if err := rqst.Pages(ctx, func(page *iam.ListServiceAccountsResponse) error {
+				accounts = append(accounts, page.Accounts...)
+				return nil
+			}); err != nil {
 				if e, ok := err.(*googleapi.Error); ok {
 					if e.Code == http.StatusForbidden {
 						// Probably (!) IAM API has not been enabled for Project (p)
@@ -99,7 +104,25 @@ func (c *IAMCollector) Collect(ch chan<- prometheus.Metric) {
 				return
 			}
 
-			for _, account := range resp.Accounts {
+			resources := []gcp.Resource{}
+			for _, account := range accounts {
+				resources = append(resources, gcp.Resource{
+					Project:  p.ProjectId,
+					Service:  "iam",
+					Type:     "service_account",
+					Location: "global",
+					Name:     account.Email,
+					State: func(disabled bool) string {
+						if disabled {
+							return "DISABLED"
+						}
+						return "ENABLED"
+					}(account.Disabled),
+				})
+			}
+			c.account.Inventory.Update(p.ProjectId, "iam", "service_account", resources)
+
+			for _, account := range accounts {
 				log.Printf("IAMCollector:go] ServiceAccount: %s", account.Name)
 
 				// Record Service Account metrics
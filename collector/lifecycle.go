@@ -0,0 +1,77 @@
+package collector
+
+import (
+	"github.com/DazWilkin/gcp-exporter/gcp"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	_ prometheus.Collector = (*LifecycleCollector)(nil)
+)
+
+// LifecycleCollector represents the creation and deletion of resources detected by the Inventory
+type LifecycleCollector struct {
+	account *gcp.Account
+
+	Created *prometheus.Desc
+	Deleted *prometheus.Desc
+}
+
+// NewLifecycleCollector returns a new LifecycleCollector
+func NewLifecycleCollector(account *gcp.Account) *LifecycleCollector {
+	subsystem := "resources"
+
+	return &LifecycleCollector{
+		account: account,
+
+		Created: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "created_total"),
+			"Number of resources created (detected between refreshes)",
+			[]string{
+				"project",
+				"service",
+				"location",
+			},
+			nil,
+		),
+		Deleted: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "deleted_total"),
+			"Number of resources deleted (detected between refreshes)",
+			[]string{
+				"project",
+				"service",
+				"location",
+			},
+			nil,
+		),
+	}
+}
+
+// Collect implements Prometheus' Collector interface and is used to collect metrics
+func (c *LifecycleCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, count := range c.account.Inventory.ChangeCounts() {
+		labels := []string{
+			count.Project,
+			count.Service,
+			count.Location,
+		}
+		ch <- prometheus.MustNewConstMetric(
+			c.Created,
+			prometheus.CounterValue,
+			float64(count.Created),
+			labels...,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.Deleted,
+			prometheus.CounterValue,
+			float64(count.Deleted),
+			labels...,
+		)
+	}
+}
+
+// Describe implements Prometheus' Collector interface and is used to describe metrics
+func (c *LifecycleCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.Created
+	ch <- c.Deleted
+}
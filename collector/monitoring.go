@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"path"
 	"sync"
 
 	"github.com/DazWilkin/gcp-exporter/gcp"
@@ -92,16 +93,35 @@ func (c *MonitoringCollector) collectAlertPolicies(ctx context.Context, wg *sync
 		defer wg.Done()
 
 		count := 0
+		resources := []gcp.Resource{}
 
 		rqst := c.monitoringService.Projects.AlertPolicies.List(parent)
 		if err := rqst.Pages(ctx, func(page *monitoring.ListAlertPoliciesResponse) error {
 			count += len(page.AlertPolicies)
+			for _, policy := range page.AlertPolicies {
+				resources = append(resources, gcp.Resource{
+					Project:  project,
+					Service:  "monitoring",
+					Type:     "alert_policy",
+					Location: "global",
+					Name:     path.Base(policy.Name),
+					State: func(enabled bool) string {
+						if enabled {
+							return "ENABLED"
+						}
+						return "DISABLED"
+					}(policy.Enabled),
+					Labels: policy.UserLabels,
+				})
+			}
 			return nil
 		}); err != nil {
 			log.Println(err)
 			return
 		}
 
+		c.account.Inventory.Update(project, "monitoring", "alert_policy", resources)
+
 		if count != 0 {
 			ch <- prometheus.MustNewConstMetric(
 				c.AlertPolicies,
@@ -152,16 +172,35 @@ func (c *MonitoringCollector) collectUptimeChecks(ctx context.Context, wg *sync.
 		defer wg.Done()
 
 		count := 0
+		resources := []gcp.Resource{}
 
 		rqst := c.monitoringService.Projects.UptimeCheckConfigs.List(parent)
 		if err := rqst.Pages(ctx, func(page *monitoring.ListUptimeCheckConfigsResponse) error {
 			count += len(page.UptimeCheckConfigs)
+			for _, check := range page.UptimeCheckConfigs {
+				resources = append(resources, gcp.Resource{
+					Project:  project,
+					Service:  "monitoring",
+					Type:     "uptime_check",
+					Location: "global",
+					Name:     path.Base(check.Name),
+					State: func(disabled bool) string {
+						if disabled {
+							return "DISABLED"
+						}
+						return "ENABLED"
+					}(check.Disabled),
+					Labels: check.UserLabels,
+				})
+			}
 			return nil
 		}); err != nil {
 			log.Println(err)
 			return
 		}
 
+		c.account.Inventory.Update(project, "monitoring", "uptime_check", resources)
+
 		if count != 0 {
 			ch <- prometheus.MustNewConstMetric(
 				c.UptimeChecks,
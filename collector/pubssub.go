@@ -72,23 +72,23 @@ func NewPubSubCollector(account *gcp.Account, endpoint string) (*PubSubCollector
 		Subscriptions: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, subsystem, "subscriptions"),
 			"Number of subscriptions",
-			[]string{
+			withResourceLabels(account,
 				"project",
 				"name",
 				"state",
 				"topic",
-			},
+			),
 			nil,
 		),
 		// https://pkg.go.dev/google.golang.org/api@v0.242.0/pubsub/v1#Topic
 		Topics: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, subsystem, "topics"),
 			"Number of topics",
-			[]string{
+			withResourceLabels(account,
 				"project",
 				"name",
 				"state",
-			},
+			),
 			nil,
 		),
 		// Up: prometheus.NewDesc(
@@ -187,26 +187,37 @@ func (c *PubSubCollector) collectSubscriptions(wg *sync.WaitGroup, ch chan<- pro
 
 	project := fmt.Sprintf("projects/%s", p.ProjectId)
 	rqst := c.pubsubService.Projects.Subscriptions.List(project)
-	resp, err := rqst.Do()
-	if err != nil {
+	resources := []gcp.Resource{}
+	if err := rqst.Pages(context.Background(), func(page *pubsub.ListSubscriptionsResponse) error {
+		for _, s := range page.Subscriptions {
+			resources = append(resources, gcp.Resource{
+				Project:  p.ProjectId,
+				Service:  "pubsub",
+				Type:     "subscription",
+				Location: "global",
+				Name:     path.Base(s.Name),
+				State:    s.State,
+				Labels:   s.Labels,
+			})
+			ch <- prometheus.MustNewConstMetric(
+				c.Subscriptions,
+				prometheus.GaugeValue,
+				1,
+				append([]string{
+					p.ProjectId,
+					// https://pkg.go.dev/path#Base
+					path.Base(s.Name),
+					s.State,
+					path.Base(s.Topic),
+				}, c.account.Labels.Values(s.Labels)...)...,
+			)
+		}
+		return nil
+	}); err != nil {
 		log.Printf("[PubSubCollector] Error listing subscriptions for %s: %v", p.ProjectId, err)
 		return
 	}
-
-	for _, s := range resp.Subscriptions {
-		ch <- prometheus.MustNewConstMetric(
-			c.Subscriptions,
-			prometheus.GaugeValue,
-			1,
-			[]string{
-				p.ProjectId,
-				// https://pkg.go.dev/path#Base
-				path.Base(s.Name),
-				s.State,
-				path.Base(s.Topic),
-			}...,
-		)
-	}
+	c.account.Inventory.Update(p.ProjectId, "pubsub", "subscription", resources)
 }
 
 // collectTopics collects topic metrics for a project
@@ -215,25 +226,36 @@ func (c *PubSubCollector) collectTopics(wg *sync.WaitGroup, ch chan<- prometheus
 
 	project := fmt.Sprintf("projects/%s", p.ProjectId)
 	rqst := c.pubsubService.Projects.Topics.List(project)
-	resp, err := rqst.Do()
-	if err != nil {
+	resources := []gcp.Resource{}
+	if err := rqst.Pages(context.Background(), func(page *pubsub.ListTopicsResponse) error {
+		for _, t := range page.Topics {
+			resources = append(resources, gcp.Resource{
+				Project:  p.ProjectId,
+				Service:  "pubsub",
+				Type:     "topic",
+				Location: "global",
+				Name:     path.Base(t.Name),
+				State:    t.State,
+				Labels:   t.Labels,
+			})
+			ch <- prometheus.MustNewConstMetric(
+				c.Topics,
+				prometheus.GaugeValue,
+				1,
+				append([]string{
+					p.ProjectId,
+					// https://pkg.go.dev/path#Base
+					path.Base(t.Name),
+					t.State,
+				}, c.account.Labels.Values(t.Labels)...)...,
+			)
+		}
+		return nil
+	}); err != nil {
 		log.Printf("[PubSubCollector] Error listing topics for %s: %v", p.ProjectId, err)
 		return
 	}
-
-	for _, t := range resp.Topics {
-		ch <- prometheus.MustNewConstMetric(
-			c.Topics,
-			prometheus.GaugeValue,
-			1,
-			[]string{
-				p.ProjectId,
-				// https://pkg.go.dev/path#Base
-				path.Base(t.Name),
-				t.State,
-			}...,
-		)
-	}
+	c.account.Inventory.Update(p.ProjectId, "pubsub", "topic", resources)
 }
 
 // Describe implements Prometheus' Collector interface and is used to describe metrics
@@ -0,0 +1,82 @@
+package collector
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/DazWilkin/gcp-exporter/gcp"
+	"google.golang.org/api/googleapi"
+)
+
+const (
+	// groupSeparator separates the label values of a grouped series' key
+	groupSeparator = "\xff"
+)
+
+// locationOf returns the location of a resource from its fully-qualified name
+// e.g. projects/{project}/locations/{location}/...
+// Returns "" if the name does not include a location
+func locationOf(name string) string {
+	parts := strings.Split(name, "/")
+	for i := 0; i < len(parts)-1; i++ {
+		if parts[i] == "locations" {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
+
+// regionOf returns the region of a zone e.g. us-central1-a ==> us-central1
+// Regions (and other locations) are returned unchanged
+func regionOf(zone string) string {
+	parts := strings.Split(zone, "-")
+	if len(parts) == 3 && len(parts[2]) == 1 {
+		return strings.Join(parts[:2], "-")
+	}
+	return zone
+}
+
+// hasPublicMember returns whether an IAM binding's members include allUsers or allAuthenticatedUsers
+// i.e. whether the binding's role is granted to anyone (authenticated or not)
+func hasPublicMember(members []string) bool {
+	for _, member := range members {
+		if member == "allUsers" || member == "allAuthenticatedUsers" {
+			return true
+		}
+	}
+	return false
+}
+
+// withResourceLabels returns a metric's label names followed by the names of the account's allowed resource labels
+func withResourceLabels(account *gcp.Account, names ...string) []string {
+	// names is copied because it may be (a slice of) another metric's label names
+	return append(append([]string{}, names...), account.Labels.Names()...)
+}
+
+// groupKey returns the key of a grouped series from its label values
+// Keys are used to aggregate series by label values that include (a variable number of) resource labels' values
+// Keys must include at least one value other than resource labels' values
+func groupKey(values ...string) string {
+	return strings.Join(values, groupSeparator)
+}
+
+// groupValues returns the label values of a grouped series from its key
+func groupValues(key string) []string {
+	return strings.Split(key, groupSeparator)
+}
+
+// logError logs errors except Forbidden errors
+// Forbidden errors are (probably) because the service's API has not been enabled in the project
+func logError(collector, project string, err error) {
+	if e, ok := err.(*googleapi.Error); ok {
+		if e.Code == http.StatusForbidden {
+			return
+		}
+
+		log.Printf("[%s] Project: %s -- Google API Error: %d [%s]", collector, project, e.Code, strings.TrimSpace(e.Message))
+		return
+	}
+
+	log.Println(err)
+}
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"path"
 	"sync"
+	"time"
 
 	"github.com/DazWilkin/gcp-exporter/gcp"
 	"github.com/prometheus/client_golang/prometheus"
@@ -24,7 +26,11 @@ type SchedulerCollector struct {
 	account          *gcp.Account
 	schedulerService *cloudscheduler.Service
 
-	Jobs *prometheus.Desc
+	Jobs                     *prometheus.Desc
+	JobInfo                  *prometheus.Desc
+	JobLastAttemptTimestamp  *prometheus.Desc
+	JobNextScheduleTimestamp *prometheus.Desc
+	JobLastAttemptStatusCode *prometheus.Desc
 }
 
 // NewSchedulerCollector returns a new SchedulerCollector
@@ -38,6 +44,13 @@ func NewSchedulerCollector(account *gcp.Account) (*SchedulerCollector, error) {
 		return nil, err
 	}
 
+	// Jobs are identified by job_name because job is the label of Prometheus' scrape job
+	jobLabels := []string{
+		"project",
+		"region",
+		"job_name",
+	}
+
 	return &SchedulerCollector{
 		account:          account,
 		schedulerService: schedulerService,
@@ -47,10 +60,34 @@ func NewSchedulerCollector(account *gcp.Account) (*SchedulerCollector, error) {
 			"Number of Jobs",
 			[]string{
 				"project",
-				// "region",
+				"region",
 			},
 			nil,
 		),
+		JobInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "job_info"),
+			"Job information including its schedule, time zone, target type (http|pubsub|app_engine) and state",
+			append(jobLabels, "schedule", "time_zone", "target_type", "state"),
+			nil,
+		),
+		JobLastAttemptTimestamp: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "job_last_attempt_timestamp_seconds"),
+			"Time the job was last attempted in Unix epoch seconds",
+			jobLabels,
+			nil,
+		),
+		JobNextScheduleTimestamp: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "job_next_schedule_timestamp_seconds"),
+			"Time the job is next scheduled in Unix epoch seconds",
+			jobLabels,
+			nil,
+		),
+		JobLastAttemptStatusCode: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "job_last_attempt_status_code"),
+			"The (google.rpc.Code) status code of the job's last attempt (0 is OK)",
+			jobLabels,
+			nil,
+		),
 	}, nil
 }
 
@@ -67,7 +104,11 @@ func (c *SchedulerCollector) Collect(ch chan<- prometheus.Metric) {
 			log.Printf("[SchedulerCollector] Project: %s", p.ProjectId)
 
 			name := fmt.Sprintf("projects/%s", p.ProjectId)
-			count := 0
+			// Counts are by region (location)
+			counts := map[string]int{}
+			resources := []gcp.Resource{}
+			// The inventory is not updated if any location fails to avoid reporting its resources as deleted
+			failed := false
 
 			rqst := c.schedulerService.Projects.Locations.List(name)
 			if err := rqst.Pages(ctx, func(page *cloudscheduler.ListLocationsResponse) error {
@@ -78,12 +119,22 @@ func (c *SchedulerCollector) Collect(ch chan<- prometheus.Metric) {
 					rqst2 := c.schedulerService.Projects.Locations.Jobs.List(name2)
 					if err := rqst2.Pages(ctx, func(page2 *cloudscheduler.ListJobsResponse) error {
 						// Count the number of Jobs
-						count += len(page2.Jobs)
-						// for _, j := range page2.Jobs {
-						// 	log.Printf("[SchedulerCollector] Job: %s", j)
-						// }
+						counts[l.LocationId] += len(page2.Jobs)
+						for _, j := range page2.Jobs {
+							c.collectJob(ch, p.ProjectId, l.LocationId, j)
+
+							resources = append(resources, gcp.Resource{
+								Project:  p.ProjectId,
+								Service:  "scheduler",
+								Type:     "job",
+								Location: l.LocationId,
+								Name:     path.Base(j.Name),
+								State:    j.State,
+							})
+						}
 						return nil
 					}); err != nil {
+						failed = true
 						if e, ok := err.(*googleapi.Error); ok {
 							log.Printf("Google API Error: %d [%s]", e.Code, e.Message)
 							return nil
@@ -109,13 +160,21 @@ func (c *SchedulerCollector) Collect(ch chan<- prometheus.Metric) {
 				return
 			}
 
-			if count != 0 {
+			if !failed {
+				c.account.Inventory.Update(p.ProjectId, "scheduler", "job", resources)
+			}
+
+			for region, count := range counts {
+				if count == 0 {
+					continue
+				}
 				ch <- prometheus.MustNewConstMetric(
 					c.Jobs,
 					prometheus.GaugeValue,
 					float64(count),
 					[]string{
 						p.ProjectId,
+						region,
 					}...,
 				)
 			}
@@ -124,7 +183,73 @@ func (c *SchedulerCollector) Collect(ch chan<- prometheus.Metric) {
 	}
 }
 
+// collectJob collects a job's metrics
+func (c *SchedulerCollector) collectJob(ch chan<- prometheus.Metric, project, region string, j *cloudscheduler.Job) {
+	labels := []string{
+		project,
+		region,
+		path.Base(j.Name),
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		c.JobInfo,
+		prometheus.GaugeValue,
+		1.0,
+		append(labels, j.Schedule, j.TimeZone, targetTypeOf(j), j.State)...,
+	)
+
+	// Jobs that have not been attempted have no last attempt time (or status)
+	if t, err := time.Parse(time.RFC3339, j.LastAttemptTime); err == nil {
+		ch <- prometheus.MustNewConstMetric(
+			c.JobLastAttemptTimestamp,
+			prometheus.GaugeValue,
+			float64(t.Unix()),
+			labels...,
+		)
+
+		// Status is omitted when the last attempt succeeded (code 0)
+		code := int64(0)
+		if j.Status != nil {
+			code = j.Status.Code
+		}
+		ch <- prometheus.MustNewConstMetric(
+			c.JobLastAttemptStatusCode,
+			prometheus.GaugeValue,
+			float64(code),
+			labels...,
+		)
+	}
+
+	// Paused jobs have no next schedule time
+	if t, err := time.Parse(time.RFC3339, j.ScheduleTime); err == nil {
+		ch <- prometheus.MustNewConstMetric(
+			c.JobNextScheduleTimestamp,
+			prometheus.GaugeValue,
+			float64(t.Unix()),
+			labels...,
+		)
+	}
+}
+
+// targetTypeOf returns the type of a job's target
+func targetTypeOf(j *cloudscheduler.Job) string {
+	switch {
+	case j.HttpTarget != nil:
+		return "http"
+	case j.PubsubTarget != nil:
+		return "pubsub"
+	case j.AppEngineHttpTarget != nil:
+		return "app_engine"
+	default:
+		return "unknown"
+	}
+}
+
 // Describe implements Prometheus' Collector interface and is used to describe metrics
 func (c *SchedulerCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.Jobs
+	ch <- c.JobInfo
+	ch <- c.JobLastAttemptTimestamp
+	ch <- c.JobNextScheduleTimestamp
+	ch <- c.JobLastAttemptStatusCode
 }
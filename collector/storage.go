@@ -3,7 +3,10 @@ package collector
 import (
 	"context"
 	"log"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/DazWilkin/gcp-exporter/gcp"
 	"github.com/prometheus/client_golang/prometheus"
@@ -17,7 +20,12 @@ type StorageCollector struct {
 	account        *gcp.Account
 	storageService *storage.Service
 
-	Buckets *prometheus.Desc
+	Buckets                   *prometheus.Desc
+	BucketInfo                *prometheus.Desc
+	BucketRetentionPeriod     *prometheus.Desc
+	BucketLifecycleRules      *prometheus.Desc
+	BucketSoftDeleteRetention *prometheus.Desc
+	BucketCreationTimestamp   *prometheus.Desc
 }
 
 // NewStorageCollector returns a StorageCollector
@@ -31,6 +39,11 @@ func NewStorageCollector(account *gcp.Account) (*StorageCollector, error) {
 		return nil, err
 	}
 
+	bucketLabels := []string{
+		"project",
+		"bucket",
+	}
+
 	return &StorageCollector{
 		account:        account,
 		storageService: storageService,
@@ -38,10 +51,47 @@ func NewStorageCollector(account *gcp.Account) (*StorageCollector, error) {
 		Buckets: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, subsystem, "buckets"),
 			"Number of buckets",
-			[]string{
+			withResourceLabels(account,
 				"project",
-				// "region",
-			},
+				"region",
+			),
+			nil,
+		),
+		BucketInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "bucket_info"),
+			"Bucket information including its location, location type, default storage class, whether versioning and uniform bucket-level access are enabled and its public access prevention",
+			withResourceLabels(account, append(bucketLabels,
+				"location",
+				"location_type",
+				"storage_class",
+				"versioning",
+				"uniform_bucket_level_access",
+				"public_access_prevention",
+			)...),
+			nil,
+		),
+		BucketRetentionPeriod: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "bucket_retention_period_seconds"),
+			"Retention period of the bucket's retention policy in seconds (0 if the bucket has no retention policy)",
+			withResourceLabels(account, append(bucketLabels, "locked")...),
+			nil,
+		),
+		BucketLifecycleRules: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "bucket_lifecycle_rules"),
+			"Number of the bucket's lifecycle rules",
+			withResourceLabels(account, bucketLabels...),
+			nil,
+		),
+		BucketSoftDeleteRetention: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "bucket_soft_delete_retention_seconds"),
+			"Duration that soft-deleted objects in the bucket are retained in seconds (0 if soft delete is disabled)",
+			withResourceLabels(account, bucketLabels...),
+			nil,
+		),
+		BucketCreationTimestamp: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, subsystem, "bucket_creation_timestamp_seconds"),
+			"Time the bucket was created in Unix epoch seconds",
+			withResourceLabels(account, bucketLabels...),
 			nil,
 		),
 	}, nil
@@ -58,30 +108,140 @@ func (c *StorageCollector) Collect(ch chan<- prometheus.Metric) {
 		go func(p *cloudresourcemanager.Project) {
 			defer wg.Done()
 			log.Printf("[StorageCollector] Project: %s", p.ProjectId)
-			resp, err := c.storageService.Buckets.List(p.ProjectId).MaxResults(500).Context(ctx).Do()
-			if err != nil {
-				log.Println(err)
+
+			// Buckets are grouped by their region (location) and allowed resource labels' values
+			// Projects without buckets report 0 (without a region and with placeholder values)
+			counts := map[string]int{}
+			resources := []gcp.Resource{}
+
+			rqst := c.storageService.Buckets.List(p.ProjectId).MaxResults(500)
+			if err := rqst.Pages(ctx, func(page *storage.Buckets) error {
+				for _, b := range page.Items {
+					resourceLabels := c.account.Labels.Values(b.Labels)
+					location := strings.ToLower(b.Location)
+
+					counts[groupKey(append([]string{p.ProjectId, location}, resourceLabels...)...)]++
+
+					resources = append(resources, gcp.Resource{
+						Project:  p.ProjectId,
+						Service:  "storage",
+						Type:     "bucket",
+						Location: location,
+						Name:     b.Name,
+						Labels:   b.Labels,
+					})
+
+					c.collectBucket(ch, p.ProjectId, b, resourceLabels)
+				}
+				return nil
+			}); err != nil {
+				logError("StorageCollector", p.ProjectId, err)
 				return
 			}
-			if resp.NextPageToken != "" {
-				log.Println("[StorageCollector] Some buckets are being excluded from the results")
+
+			c.account.Inventory.Update(p.ProjectId, "storage", "bucket", resources)
+
+			if len(resources) == 0 {
+				counts[groupKey(append([]string{p.ProjectId, ""}, c.account.Labels.Values(nil)...)...)] = 0
+			}
+			for k, count := range counts {
+				ch <- prometheus.MustNewConstMetric(
+					c.Buckets,
+					prometheus.GaugeValue,
+					float64(count),
+					groupValues(k)...,
+				)
 			}
-			// for _, b := range resp.Items {
-			// }
-			ch <- prometheus.MustNewConstMetric(
-				c.Buckets,
-				prometheus.GaugeValue,
-				float64(len(resp.Items)),
-				[]string{
-					p.ProjectId,
-				}...,
-			)
 		}(p)
 	}
 	wg.Wait()
 }
 
+// collectBucket collects a bucket's metrics
+func (c *StorageCollector) collectBucket(ch chan<- prometheus.Metric, project string, b *storage.Bucket, resourceLabels []string) {
+	labels := append([]string{
+		project,
+		b.Name,
+	}, resourceLabels...)
+
+	versioning := b.Versioning != nil && b.Versioning.Enabled
+	uniformBucketLevelAccess := false
+	publicAccessPrevention := ""
+	if b.IamConfiguration != nil {
+		uniformBucketLevelAccess = b.IamConfiguration.UniformBucketLevelAccess != nil && b.IamConfiguration.UniformBucketLevelAccess.Enabled
+		publicAccessPrevention = b.IamConfiguration.PublicAccessPrevention
+	}
+	ch <- prometheus.MustNewConstMetric(
+		c.BucketInfo,
+		prometheus.GaugeValue,
+		1.0,
+		append([]string{
+			project,
+			b.Name,
+			strings.ToLower(b.Location),
+			b.LocationType,
+			b.StorageClass,
+			strconv.FormatBool(versioning),
+			strconv.FormatBool(uniformBucketLevelAccess),
+			publicAccessPrevention,
+		}, resourceLabels...)...,
+	)
+
+	var retentionPeriod int64
+	locked := false
+	if b.RetentionPolicy != nil {
+		retentionPeriod = b.RetentionPolicy.RetentionPeriod
+		locked = b.RetentionPolicy.IsLocked
+	}
+	ch <- prometheus.MustNewConstMetric(
+		c.BucketRetentionPeriod,
+		prometheus.GaugeValue,
+		float64(retentionPeriod),
+		append([]string{
+			project,
+			b.Name,
+			strconv.FormatBool(locked),
+		}, resourceLabels...)...,
+	)
+
+	rules := 0
+	if b.Lifecycle != nil {
+		rules = len(b.Lifecycle.Rule)
+	}
+	ch <- prometheus.MustNewConstMetric(
+		c.BucketLifecycleRules,
+		prometheus.GaugeValue,
+		float64(rules),
+		labels...,
+	)
+
+	var softDeleteRetention int64
+	if b.SoftDeletePolicy != nil {
+		softDeleteRetention = b.SoftDeletePolicy.RetentionDurationSeconds
+	}
+	ch <- prometheus.MustNewConstMetric(
+		c.BucketSoftDeleteRetention,
+		prometheus.GaugeValue,
+		float64(softDeleteRetention),
+		labels...,
+	)
+
+	if t, err := time.Parse(time.RFC3339, b.TimeCreated); err == nil {
+		ch <- prometheus.MustNewConstMetric(
+			c.BucketCreationTimestamp,
+			prometheus.GaugeValue,
+			float64(t.Unix()),
+			labels...,
+		)
+	}
+}
+
 // Describe implements Prometheus' Collector interface and is used to describe metrics
 func (c *StorageCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.Buckets
+	ch <- c.BucketInfo
+	ch <- c.BucketRetentionPeriod
+	ch <- c.BucketLifecycleRules
+	ch <- c.BucketSoftDeleteRetention
+	ch <- c.BucketCreationTimestamp
 }
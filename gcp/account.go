@@ -13,13 +13,22 @@ type Account struct {
 
 	// Projects list that's account across Collectors
 	Projects []*cloudresourcemanager.Project
+
+	// Inventory of resources that's shared across Collectors
+	Inventory *Inventory
+
+	// Labels are the resource labels that Collectors add to metrics
+	// Labels must be set before Collectors are created
+	Labels *LabelAllowlist
 }
 
 // NewAccount creates a new Account
 func NewAccount() *Account {
 	projects := []*cloudresourcemanager.Project{}
 	return &Account{
-		Projects: projects,
+		Projects:  projects,
+		Inventory: NewInventory(),
+		Labels:    NewLabelAllowlist(nil, ""),
 	}
 }
 
@@ -27,6 +36,18 @@ func NewAccount() *Account {
 func (x *Account) Update(projects []*cloudresourcemanager.Project) {
 	log.Printf("[Update] replacing projects")
 	x.mu.Lock()
+	previous := x.Projects
 	x.Projects = projects
 	x.mu.Unlock()
+
+	// Projects that are no longer enumerated would otherwise remain in the inventory
+	current := map[string]bool{}
+	for _, p := range projects {
+		current[p.ProjectId] = true
+	}
+	for _, p := range previous {
+		if !current[p.ProjectId] {
+			x.Inventory.RemoveProject(p.ProjectId)
+		}
+	}
 }
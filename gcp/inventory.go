@@ -0,0 +1,222 @@
+package gcp
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Resource represents a GCP resource that's enumerated by a Collector
+type Resource struct {
+	Project  string            `json:"project"`
+	Service  string            `json:"service"`
+	Type     string            `json:"type"`
+	Location string            `json:"location"`
+	Name     string            `json:"name"`
+	State    string            `json:"state"`
+	Labels   map[string]string `json:"labels,omitempty"`
+
+	// FirstSeen is the time the resource was first enumerated by the exporter
+	// It is set by the Inventory
+	FirstSeen time.Time `json:"first_seen"`
+}
+
+// inventoryKey identifies the set of resources of a type of a service in a project
+// Collectors enumerate resources by project and so each key is replaced independently
+type inventoryKey struct {
+	project      string
+	service      string
+	resourceType string
+}
+
+// Inventory represents the resources most recently enumerated by the Collectors
+type Inventory struct {
+	mu sync.RWMutex
+
+	resources map[inventoryKey][]Resource
+
+	// Changes (creations, deletions) detected between refreshes
+	counts      map[changeKey]*ChangeCount
+	subscribers []func(Change)
+}
+
+// NewInventory creates a new Inventory
+func NewInventory() *Inventory {
+	return &Inventory{
+		resources: map[inventoryKey][]Resource{},
+		counts:    map[changeKey]*ChangeCount{},
+	}
+}
+
+// Update is a method that transactionally replaces the resources of a type of a service in a project
+func (x *Inventory) Update(project, service, resourceType string, resources []Resource) {
+	log.Printf("[Update] replacing %s %s resources (project: %s)", service, resourceType, project)
+	key := inventoryKey{
+		project:      project,
+		service:      service,
+		resourceType: resourceType,
+	}
+
+	now := time.Now()
+
+	x.mu.Lock()
+	previous, refreshed := x.resources[key]
+	resources = stamp(previous, resources, now)
+	x.resources[key] = resources
+	x.initCounts(resources)
+
+	// The first update of a key establishes the baseline
+	// Only subsequent updates are compared to detect changes
+	changes := []Change{}
+	if refreshed {
+		changes = diff(previous, resources, now)
+		x.count(changes)
+	}
+	subscribers := x.subscribers
+	x.mu.Unlock()
+
+	for _, change := range changes {
+		log.Printf("[Update] %s %s %s: %s (project: %s)", change.Action, service, resourceType, change.Resource.Name, project)
+		for _, subscriber := range subscribers {
+			subscriber(change)
+		}
+	}
+}
+
+// RemoveProject is a method that removes the resources of a project that's no longer enumerated
+// The project's resources are reported as deleted
+func (x *Inventory) RemoveProject(project string) {
+	log.Printf("[RemoveProject] removing resources (project: %s)", project)
+	now := time.Now()
+
+	x.mu.Lock()
+	changes := []Change{}
+	for key, previous := range x.resources {
+		if key.project != project {
+			continue
+		}
+		changes = append(changes, diff(previous, nil, now)...)
+		delete(x.resources, key)
+	}
+	x.count(changes)
+	subscribers := x.subscribers
+	x.mu.Unlock()
+
+	for _, change := range changes {
+		log.Printf("[RemoveProject] %s %s %s: %s (project: %s)", change.Action, change.Resource.Service, change.Resource.Type, change.Resource.Name, project)
+		for _, subscriber := range subscribers {
+			subscriber(change)
+		}
+	}
+}
+
+// Resources is a method that returns all the resources ordered by project, service, type, location and name
+func (x *Inventory) Resources() []Resource {
+	x.mu.RLock()
+	resources := []Resource{}
+	for _, rr := range x.resources {
+		resources = append(resources, rr...)
+	}
+	x.mu.RUnlock()
+
+	sort.Slice(resources, func(i, j int) bool {
+		a, b := resources[i], resources[j]
+		if a.Project != b.Project {
+			return a.Project < b.Project
+		}
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Location != b.Location {
+			return a.Location < b.Location
+		}
+		return a.Name < b.Name
+	})
+
+	return resources
+}
+
+// Filter represents a filter of resources
+// A resource matches if, for each non-empty field, the resource's value is one of the field's values
+type Filter struct {
+	Projects  []string
+	Services  []string
+	Locations []string
+	States    []string
+}
+
+// Matches is a method that determines whether a resource matches the filter
+func (f Filter) Matches(r Resource) bool {
+	match := func(values []string, value string) bool {
+		return len(values) == 0 || slices.Contains(values, value)
+	}
+	return match(f.Projects, r.Project) &&
+		match(f.Services, r.Service) &&
+		match(f.Locations, r.Location) &&
+		match(f.States, r.State)
+}
+
+// Filter is a method that returns the resources that match the filter
+func (x *Inventory) Filter(f Filter) []Resource {
+	resources := []Resource{}
+	for _, r := range x.Resources() {
+		if f.Matches(r) {
+			resources = append(resources, r)
+		}
+	}
+	return resources
+}
+
+// InventoryFormats are the formats supported by WriteResources
+var InventoryFormats = []string{"table", "json", "csv"}
+
+// WriteResources writes resources to w in one of the InventoryFormats
+func WriteResources(w io.Writer, format string, resources []Resource) error {
+	header := []string{"PROJECT", "SERVICE", "TYPE", "LOCATION", "NAME", "STATE"}
+	row := func(r Resource) []string {
+		return []string{r.Project, r.Service, r.Type, r.Location, r.Name, r.State}
+	}
+
+	switch format {
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, cols := range append([][]string{header}, rowsOf(resources, row)...) {
+			if _, err := fmt.Fprintln(tw, strings.Join(cols, "\t")); err != nil {
+				return err
+			}
+		}
+		return tw.Flush()
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(resources)
+	case "csv":
+		cw := csv.NewWriter(w)
+		if err := cw.WriteAll(append([][]string{header}, rowsOf(resources, row)...)); err != nil {
+			return err
+		}
+		return cw.Error()
+	default:
+		return fmt.Errorf("unsupported inventory format '%s' (expected one of %v)", format, InventoryFormats)
+	}
+}
+
+// rowsOf converts resources into rows of columns
+func rowsOf(resources []Resource, row func(Resource) []string) [][]string {
+	rows := make([][]string, 0, len(resources))
+	for _, r := range resources {
+		rows = append(rows, row(r))
+	}
+	return rows
+}
@@ -0,0 +1,86 @@
+package gcp
+
+import (
+	"log"
+	"regexp"
+	"strings"
+)
+
+const (
+	// labelPrefix prefixes the metric label names of resource labels to avoid conflicts with the metrics' labels
+	labelPrefix = "label_"
+)
+
+var (
+	// invalidLabelChars are characters that are not valid in Prometheus label names
+	invalidLabelChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+)
+
+// LabelAllowlist represents the resource labels that are added to metrics as metric labels
+type LabelAllowlist struct {
+	// keys are the resource labels' keys
+	keys []string
+	// names are the (sanitized) metric labels' names
+	names []string
+	// placeholder is the metric label value used when a resource does not have the label
+	placeholder string
+}
+
+// NewLabelAllowlist returns a new LabelAllowlist
+// Keys that are empty or whose sanitized names duplicate another key's are ignored
+func NewLabelAllowlist(keys []string, placeholder string) *LabelAllowlist {
+	l := &LabelAllowlist{
+		placeholder: placeholder,
+	}
+
+	seen := map[string]bool{}
+	for _, key := range keys {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		name := SanitizeLabelName(key)
+		if seen[name] {
+			log.Printf("[NewLabelAllowlist] Ignoring label (%s) because its name (%s) is a duplicate", key, name)
+			continue
+		}
+		seen[name] = true
+
+		l.keys = append(l.keys, key)
+		l.names = append(l.names, name)
+	}
+
+	return l
+}
+
+// SanitizeLabelName returns a valid Prometheus label name for a resource label key
+// e.g. cost-center ==> label_cost_center
+func SanitizeLabelName(key string) string {
+	return labelPrefix + invalidLabelChars.ReplaceAllString(key, "_")
+}
+
+// Names is a method that returns the metric labels' names
+// Names are appended to metrics' label names
+func (l *LabelAllowlist) Names() []string {
+	if l == nil {
+		return nil
+	}
+	return l.names
+}
+
+// Values is a method that returns a resource's values for the allowed labels
+// Values are in the same order as Names and use the placeholder for missing labels
+func (l *LabelAllowlist) Values(labels map[string]string) []string {
+	if l == nil {
+		return nil
+	}
+	values := make([]string, len(l.keys))
+	for i, key := range l.keys {
+		value, ok := labels[key]
+		if !ok || value == "" {
+			value = l.placeholder
+		}
+		values[i] = value
+	}
+	return values
+}
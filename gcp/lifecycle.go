@@ -0,0 +1,204 @@
+package gcp
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// Created is the action of a Change when a resource appears between refreshes
+	Created = "created"
+	// Deleted is the action of a Change when a resource disappears between refreshes
+	Deleted = "deleted"
+)
+
+// Change represents a resource that was created or deleted between refreshes of the Inventory
+type Change struct {
+	Time     time.Time `json:"time"`
+	Action   string    `json:"action"`
+	Resource Resource  `json:"resource"`
+}
+
+// changeKey identifies the resources whose changes are counted together
+type changeKey struct {
+	project  string
+	service  string
+	location string
+}
+
+// ChangeCount represents the number of resources that were created and deleted
+// in a location of a service in a project since the exporter started
+type ChangeCount struct {
+	Project  string
+	Service  string
+	Location string
+
+	Created uint64
+	Deleted uint64
+}
+
+// identity is the identity of a resource within an inventoryKey
+type identity struct {
+	location string
+	name     string
+}
+
+// identities returns the resources by their identity
+func identities(resources []Resource) map[identity]Resource {
+	m := make(map[identity]Resource, len(resources))
+	for _, r := range resources {
+		m[identity{location: r.Location, name: r.Name}] = r
+	}
+	return m
+}
+
+// stamp returns a copy of the current resources with their FirstSeen time
+// Resources that were previously seen retain their FirstSeen time, otherwise it's now
+func stamp(previous, current []Resource, now time.Time) []Resource {
+	before := identities(previous)
+
+	resources := make([]Resource, 0, len(current))
+	for _, r := range current {
+		r.FirstSeen = now
+		if p, ok := before[identity{location: r.Location, name: r.Name}]; ok {
+			r.FirstSeen = p.FirstSeen
+		}
+		resources = append(resources, r)
+	}
+	return resources
+}
+
+// diff compares the previous and current resources and returns the changes between them
+func diff(previous, current []Resource, now time.Time) []Change {
+	before := identities(previous)
+	after := identities(current)
+
+	changes := []Change{}
+	for id, r := range after {
+		if _, ok := before[id]; !ok {
+			changes = append(changes, Change{Time: now, Action: Created, Resource: r})
+		}
+	}
+	for id, r := range before {
+		if _, ok := after[id]; !ok {
+			changes = append(changes, Change{Time: now, Action: Deleted, Resource: r})
+		}
+	}
+
+	// Order changes for deterministic logging and notifications
+	sort.Slice(changes, func(i, j int) bool {
+		a, b := changes[i], changes[j]
+		if a.Action != b.Action {
+			return a.Action < b.Action
+		}
+		if a.Resource.Location != b.Resource.Location {
+			return a.Resource.Location < b.Resource.Location
+		}
+		return a.Resource.Name < b.Resource.Name
+	})
+
+	return changes
+}
+
+// counter is a method that returns the change count of a resource's project, service and location
+// Change counts are created (with zero counts) when first needed
+// It must be called with the Inventory's lock held
+func (x *Inventory) counter(r Resource) *ChangeCount {
+	key := changeKey{
+		project:  r.Project,
+		service:  r.Service,
+		location: r.Location,
+	}
+	c, ok := x.counts[key]
+	if !ok {
+		c = &ChangeCount{
+			Project:  key.project,
+			Service:  key.service,
+			Location: key.location,
+		}
+		x.counts[key] = c
+	}
+	return c
+}
+
+// initCounts is a method that ensures the change counts of the resources' projects, services and locations exist
+// Counts start at zero so that the first change is an increase
+// It must be called with the Inventory's lock held
+func (x *Inventory) initCounts(resources []Resource) {
+	for _, r := range resources {
+		x.counter(r)
+	}
+}
+
+// count is a method that increments the change counts
+// It must be called with the Inventory's lock held
+func (x *Inventory) count(changes []Change) {
+	for _, change := range changes {
+		c := x.counter(change.Resource)
+
+		switch change.Action {
+		case Created:
+			c.Created++
+		case Deleted:
+			c.Deleted++
+		}
+	}
+}
+
+// ChangeCounts is a method that returns the number of changes by project, service and location
+func (x *Inventory) ChangeCounts() []ChangeCount {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	counts := make([]ChangeCount, 0, len(x.counts))
+	for _, c := range x.counts {
+		counts = append(counts, *c)
+	}
+	return counts
+}
+
+// Subscribe is a method that registers a function that's called with every Change
+// Functions are called synchronously by Update and should not block
+func (x *Inventory) Subscribe(f func(Change)) {
+	x.mu.Lock()
+	x.subscribers = append(x.subscribers, f)
+	x.mu.Unlock()
+}
+
+// ChangeLog represents a file to which Changes are appended as JSON Lines
+type ChangeLog struct {
+	mu sync.Mutex
+
+	file *os.File
+	enc  *json.Encoder
+}
+
+// NewChangeLog creates a new ChangeLog that appends to the file at path
+func NewChangeLog(path string) (*ChangeLog, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChangeLog{
+		file: file,
+		enc:  json.NewEncoder(file),
+	}, nil
+}
+
+// Write is a method that appends a Change to the log
+func (x *ChangeLog) Write(change Change) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	// Encode writes a trailing newline
+	return x.enc.Encode(change)
+}
+
+// Close is a method that closes the log's file
+func (x *ChangeLog) Close() error {
+	return x.file.Close()
+}
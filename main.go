@@ -1,19 +1,30 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"html/template"
 	"log"
 	"net/http"
 	_ "net/http/pprof"
+	"os"
+	"os/signal"
 	"runtime"
+	"slices"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/DazWilkin/gcp-exporter/collector"
 	"github.com/DazWilkin/gcp-exporter/gcp"
+	"github.com/DazWilkin/gcp-exporter/pricing"
+	"github.com/DazWilkin/gcp-exporter/remotewrite"
+	"github.com/DazWilkin/gcp-exporter/webhook"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"google.golang.org/api/cloudbilling/v1"
 )
 
 var (
@@ -36,25 +47,65 @@ var (
 	profilingEnabled  = flag.Bool("profiling_enabled", false, "Enable profiling endpoint")
 	profilingEndpoint = flag.String("profiling_endpoint", ":6060", "The endpoint of the profiling server")
 
-	disableArtifactRegistryCollector = flag.Bool("collector.artifact_registry.disable", false, "Disables the metrics collector for the Artifact Registry")
-	disableCloudRunCollector         = flag.Bool("collector.cloud_run.disable", false, "Disables the metrics collector for Cloud Run")
-	disableComputeCollector          = flag.Bool("collector.compute.disable", false, "Disables the metrics collector for Compute Engine")
-	disableEndpointsCollector        = flag.Bool("collector.endpoints.disable", false, "Disables the metrics collector for Cloud Endpoints")
-	disableEventarcCollector         = flag.Bool("collector.eventarc.disable", false, "Disables the metrics collector for Cloud Eventarc")
-	disableFunctionsCollector        = flag.Bool("collector.functions.disable", false, "Disables the metrics collector for Cloud Functions")
-	disableIAMCollector              = flag.Bool("collector.iam.disable", false, "Disables the metrics collector for Cloud IAM")
-	disableGKECollector              = flag.Bool("collector.gke.disable", false, "Disables the metrics collector for Google Kubernetes Engine (GKE)")
-	disableLoggingCollector          = flag.Bool("collector.logging.disable", false, "Disables the metrics collector for Cloud Logging")
-	disableMonitoringCollector       = flag.Bool("collector.monitoring.disable", false, "Disables the metrics collector for Cloud Monitoring")
-	disablePubSubCollector           = flag.Bool("collector.pubsub.disable", false, "Disables the metrics collector for Cloud Pub/Sub")
-	disableSchedulerCollector        = flag.Bool("collector.scheduler.disable", false, "Disables the metrics collector for Cloud Scheduler")
-	disableStorageCollector          = flag.Bool("collector.storage.disable", false, "Disables the metrics collector for Cloud Storage")
+	disableAcceleratorsCollector          = flag.Bool("collector.accelerators.disable", false, "Disables the metrics collector for accelerators (e.g. GPUs) attached to Compute Engine instances and GKE nodes")
+	disableArtifactRegistryCollector      = flag.Bool("collector.artifact_registry.disable", false, "Disables the metrics collector for the Artifact Registry")
+	disableBillingCollector               = flag.Bool("collector.billing.disable", false, "Disables the metrics collector for Cloud Billing")
+	disableCloudRunCollector              = flag.Bool("collector.cloud_run.disable", false, "Disables the metrics collector for Cloud Run")
+	disableComputeCollector               = flag.Bool("collector.compute.disable", false, "Disables the metrics collector for Compute Engine")
+	disableComputeDisksCollector          = flag.Bool("collector.compute.disks.disable", false, "Disables the metrics collector for Compute Engine persistent disks, snapshots and images")
+	disableComputeLoadBalancingCollector  = flag.Bool("collector.compute.load_balancing.disable", false, "Disables the metrics collector for Cloud Load Balancing backend services (and their health), URL maps and target proxies")
+	disableComputeNetworksCollector       = flag.Bool("collector.compute.networks.disable", false, "Disables the metrics collector for Compute Engine VPC networks and subnetworks")
+	disableComputeInstanceGroupsCollector = flag.Bool("collector.compute.instance_groups.disable", false, "Disables the metrics collector for Compute Engine instance groups and managed instance groups")
+	disableEndpointsCollector             = flag.Bool("collector.endpoints.disable", false, "Disables the metrics collector for Cloud Endpoints")
+	disableEventarcCollector              = flag.Bool("collector.eventarc.disable", false, "Disables the metrics collector for Cloud Eventarc")
+	disableFunctionsCollector             = flag.Bool("collector.functions.disable", false, "Disables the metrics collector for Cloud Functions")
+	disableIAMCollector                   = flag.Bool("collector.iam.disable", false, "Disables the metrics collector for Cloud IAM")
+	disableGKECollector                   = flag.Bool("collector.gke.disable", false, "Disables the metrics collector for Google Kubernetes Engine (GKE)")
+	disableLoggingCollector               = flag.Bool("collector.logging.disable", false, "Disables the metrics collector for Cloud Logging")
+	disableMonitoringCollector            = flag.Bool("collector.monitoring.disable", false, "Disables the metrics collector for Cloud Monitoring")
+	disablePubSubCollector                = flag.Bool("collector.pubsub.disable", false, "Disables the metrics collector for Cloud Pub/Sub")
+	disableSchedulerCollector             = flag.Bool("collector.scheduler.disable", false, "Disables the metrics collector for Cloud Scheduler")
+	disableStorageCollector               = flag.Bool("collector.storage.disable", false, "Disables the metrics collector for Cloud Storage")
 
 	endpointPubSub = flag.String("collector.pubsub.endpoint", "", "The endpoint of the Pub/Sub service or emulator")
 
-	enableExtendedMetricsGKECollector = flag.Bool("collector.gke.extendedMetrics.enable", false, "Enable the metrics collector for Google Kubernetes Engine (GKE) to collect ControlPlane and NodePool metrics")
+	enableCostCollector          = flag.Bool("collector.cost.enable", false, "Enables the metrics collector for the estimated cost of running resources")
+	pricesCostCollector          = flag.String("collector.cost.prices", "", "The path of a YAML or JSON price table used to estimate costs")
+	refreshIntervalCostCollector = flag.Duration("collector.cost.refresh_interval", 0, "The interval between refreshes of the price table from the Cloud Billing Catalog API. If 0, prices are not refreshed")
+
+	lookbackCloudRunCollector = flag.Duration("collector.cloud_run.executions.lookback", 24*time.Hour, "The window within which Cloud Run job executions are enumerated")
+
+	enableInstanceInfoComputeCollector = flag.Bool("collector.compute.instanceInfo.enable", false, "Enable the metrics collector for Compute Engine to collect per-instance information and timestamps")
+	enableExtendedMetricsGKECollector  = flag.Bool("collector.gke.extendedMetrics.enable", false, "Enable the metrics collector for Google Kubernetes Engine (GKE) to collect ControlPlane and NodePool metrics")
+
+	remoteWriteURL      = flag.String("remote_write.url", "", "The URL of a Prometheus remote-write endpoint. If set, metrics are pushed to this endpoint instead of being served")
+	remoteWriteHeaders  = remotewrite.Headers{}
+	remoteWriteInterval = flag.Duration("remote_write.interval", 0, "The interval between pushes to the remote-write endpoint. If 0, metrics are pushed once")
+	remoteWriteJob      = flag.String("remote_write.job", "gcp-exporter", "The value of the job label added to metrics pushed to the remote-write endpoint")
+	remoteWriteTimeout  = flag.Duration("remote_write.timeout", 30*time.Second, "The timeout for requests to the remote-write endpoint")
+
+	inventoryFormat = flag.String("inventory.format", "table", "The output format of the inventory subcommand (table, json or csv)")
+
+	labelsAllowlist   = flag.String("labels.allowlist", "", "Comma-separated list of resource labels (e.g. team,env) that are added to metrics as metric labels (prefixed label_)")
+	labelsRequired    = flag.String("labels.required", "", "Comma-separated list of labels (e.g. owner,env) that resources are required to have. If set, resources missing the labels are reported")
+	labelsPlaceholder = flag.String("labels.placeholder", "unknown", "The metric label value used when a resource does not have an allowed label")
+
+	lifecycleLog = flag.String("lifecycle.log", "", "The path of a file to which resource creations and deletions are appended as JSON Lines")
+
+	webhookURLs     = webhook.URLs{}
+	webhookFormat   = flag.String("webhook.format", "generic", "The payload format of webhook notifications (generic or slack)")
+	webhookDryRun   = flag.Bool("webhook.dry_run", false, "Log webhook notifications instead of posting them")
+	webhookInterval = flag.Duration("webhook.interval", time.Second, "The minimum interval between webhook notifications")
+	webhookWindow   = flag.Duration("webhook.dedup_window", time.Hour, "The window within which duplicate webhook notifications are suppressed")
+	webhookTimeout  = flag.Duration("webhook.timeout", 10*time.Second, "The timeout for requests to webhooks")
+	webhookQueue    = flag.Int("webhook.queue_size", 100, "The number of webhook notifications that may be pending. When the queue is full, notifications are dropped")
 )
 
+func init() {
+	flag.Var(remoteWriteHeaders, "remote_write.header", "An HTTP header ('Name: Value') added to requests to the remote-write endpoint. May be repeated")
+	flag.Var(&webhookURLs, "webhook.url", "The URL of a webhook that's notified when resources are created or deleted. May be repeated")
+}
+
 const (
 	rootTemplate = `<!DOCTYPE html>
 <html lang="en">
@@ -66,6 +117,7 @@ const (
 	<h2>Google Cloud Platform Resources Exporter</h2>
 	<ul>
 		<li><a href="{{.MetricsPath}}">metrics</a></li>
+		<li><a href="/inventory">inventory</a></li>
 		<li><a href="/healthz">healthz</a></li>
 	</ul>
 <body>
@@ -96,6 +148,25 @@ func handleRoot(w http.ResponseWriter, _ *http.Request) {
 	}
 }
 
+func handleInventory(inventory *gcp.Inventory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Query parameters may be repeated e.g. ?project=x&project=y
+		q := r.URL.Query()
+		filter := gcp.Filter{
+			Projects:  q["project"],
+			Services:  q["service"],
+			Locations: q["location"],
+			States:    q["state"],
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := gcp.WriteResources(w, "json", inventory.Filter(filter)); err != nil {
+			msg := "error writing inventory handler"
+			log.Printf("[handleInventory] %s: %v", msg, err)
+		}
+	}
+}
+
 func must(collector prometheus.Collector, err error) prometheus.Collector {
 	if err != nil {
 		log.Fatal(err)
@@ -105,7 +176,23 @@ func must(collector prometheus.Collector, err error) prometheus.Collector {
 }
 
 func main() {
-	flag.Parse()
+	// The inventory subcommand runs the collectors once and prints the resources instead of serving metrics
+	// e.g. gcp-exporter inventory --inventory.format=json
+	inventoryMode := len(os.Args) > 1 && os.Args[1] == "inventory"
+	if inventoryMode {
+		if err := flag.CommandLine.Parse(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		if !slices.Contains(gcp.InventoryFormats, *inventoryFormat) {
+			log.Fatalf("[main] `--inventory.format` must be one of %v", gcp.InventoryFormats)
+		}
+	} else {
+		flag.Parse()
+	}
+
+	if *disableComputeCollector && *enableInstanceInfoComputeCollector {
+		log.Println("[main] `--collector.compute.instanceInfo.enable` has no effect because `--collector.compute.disable=true`")
+	}
 
 	if *disableGKECollector && *enableExtendedMetricsGKECollector {
 		log.Println("[main] `--enabledExtendedMetricsGKECollector` has no effect because `--disableGKECollector=true`")
@@ -130,33 +217,151 @@ func main() {
 		log.Printf("[main] Using Pub/Sub emulator (%s)", *endpointPubSub)
 	}
 
+	if !*enableCostCollector && (*pricesCostCollector != "" || *refreshIntervalCostCollector != 0) {
+		log.Println("[main] `--collector.cost.prices` and `--collector.cost.refresh_interval` have no effect because `--collector.cost.enable=false`")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Objects that holds GCP-specific resources (e.g. projects)
 	account := gcp.NewAccount()
 
-	registry := prometheus.NewRegistry()
-	registry.MustRegister(collector.NewExporterCollector(OSVersion, GoVersion, GitCommit, StartTime))
+	// Resource labels are added to metrics as metric labels
+	// The labels must be set before the collectors are created
+	if *labelsAllowlist != "" {
+		account.Labels = gcp.NewLabelAllowlist(strings.Split(*labelsAllowlist, ","), *labelsPlaceholder)
+		log.Printf("[main] Adding resource labels to metrics: %s", strings.Join(account.Labels.Names(), ","))
+	}
+
+	// Resource creations and deletions are detected when the inventory is refreshed
+	if *lifecycleLog != "" {
+		changeLog, err := gcp.NewChangeLog(*lifecycleLog)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer changeLog.Close()
+
+		log.Printf("[main] Logging resource changes (%s)", *lifecycleLog)
+		account.Inventory.Subscribe(func(change gcp.Change) {
+			if err := changeLog.Write(change); err != nil {
+				log.Printf("[main] unable to write change: %v", err)
+			}
+		})
+	}
+
+	// Prices are used to estimate the cost of running resources
+	prices := pricing.NewTable()
+	if *enableCostCollector {
+		if *pricesCostCollector != "" {
+			var err error
+			prices, err = pricing.Load(*pricesCostCollector)
+			if err != nil {
+				log.Fatal(err)
+			}
+			log.Printf("[main] Loaded price table (%s)", *pricesCostCollector)
+		}
+
+		if *refreshIntervalCostCollector > 0 {
+			billingService, err := cloudbilling.NewService(ctx)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			go func() {
+				ticker := time.NewTicker(*refreshIntervalCostCollector)
+				defer ticker.Stop()
+
+				for {
+					if err := prices.Refresh(ctx, billingService); err != nil {
+						log.Printf("[main] unable to refresh prices: %v", err)
+					}
+
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+					}
+				}
+			}()
+		}
+	}
+
+	// Resource creations and deletions are notified to webhooks
+	if len(webhookURLs) > 0 {
+		notifier, err := webhook.NewNotifier(webhookURLs, *webhookFormat, *webhookDryRun, *webhookInterval, *webhookWindow, *webhookTimeout, *webhookQueue)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		log.Printf("[main] Notifying resource changes to %d webhook(s)", len(webhookURLs))
+		account.Inventory.Subscribe(notifier.Notify)
+		go notifier.Run(ctx)
+	}
 
 	// ProjectCollector is a special case
 	// When it runs it replaces the Exporter's list of GCP projects
 	// The other collectors are dependent on this list of projects
-	registry.MustRegister(must(collector.NewProjectsCollector(account, *filter, *pagesize)))
+	// It is registered separately so that it is gathered before the other collectors
+	projects := prometheus.NewRegistry()
+	projects.MustRegister(collector.NewExporterCollector(OSVersion, GoVersion, GitCommit, StartTime))
+	projects.MustRegister(must(collector.NewProjectsCollector(account, *filter, *pagesize)))
+
+	registry := prometheus.NewRegistry()
+
+	// CostCollector is disabled unless it's enabled
+	disableCostCollector := !*enableCostCollector
+
+	// Compute Engine sub-collectors are disabled if the Compute Engine collector is disabled
+	disableComputeDisksCollector := *disableComputeCollector || *disableComputeDisksCollector
+	disableComputeInstanceGroupsCollector := *disableComputeCollector || *disableComputeInstanceGroupsCollector
+	disableComputeLoadBalancingCollector := *disableComputeCollector || *disableComputeLoadBalancingCollector
+	disableComputeNetworksCollector := *disableComputeCollector || *disableComputeNetworksCollector
 
 	collectorConfigs := map[string]struct {
 		collector prometheus.Collector
 		disable   *bool
 	}{
+		"accelerators": {
+			must(collector.NewAcceleratorsCollector(account)),
+			disableAcceleratorsCollector,
+		},
 		"artifact_registry": {
 			must(collector.NewArtifactRegistryCollector(account)),
 			disableArtifactRegistryCollector,
 		},
+		"billing": {
+			must(collector.NewBillingCollector(account)),
+			disableBillingCollector,
+		},
 		"cloud_run": {
-			must(collector.NewCloudRunCollector(account)),
+			must(collector.NewCloudRunCollector(account, *lookbackCloudRunCollector)),
 			disableCloudRunCollector,
 		},
 		"compute": {
-			must(collector.NewComputeCollector(account)),
+			must(collector.NewComputeCollector(account, *enableInstanceInfoComputeCollector)),
 			disableComputeCollector,
 		},
+		"compute_disks": {
+			must(collector.NewComputeDisksCollector(account)),
+			&disableComputeDisksCollector,
+		},
+		"compute_instance_groups": {
+			must(collector.NewComputeInstanceGroupsCollector(account)),
+			&disableComputeInstanceGroupsCollector,
+		},
+		"compute_load_balancing": {
+			must(collector.NewComputeLoadBalancingCollector(account)),
+			&disableComputeLoadBalancingCollector,
+		},
+		"compute_networks": {
+			must(collector.NewComputeNetworksCollector(account)),
+			&disableComputeNetworksCollector,
+		},
+		"cost": {
+			must(collector.NewCostCollector(account, prices)),
+			&disableCostCollector,
+		},
 		"endpoints": {
 			must(collector.NewEndpointsCollector(account)),
 			disableEndpointsCollector,
@@ -206,10 +411,48 @@ func main() {
 		}
 	}
 
+	// LifecycleCollector reports the changes detected by the other collectors
+	// It is registered separately so that it is gathered after the other collectors
+	changes := prometheus.NewRegistry()
+	changes.MustRegister(collector.NewLifecycleCollector(account))
+
+	// LabelComplianceCollector checks the resources enumerated by the other collectors
+	if *labelsRequired != "" {
+		changes.MustRegister(collector.NewLabelComplianceCollector(account, strings.Split(*labelsRequired, ",")))
+	}
+
+	// Gatherers are gathered in order
+	// A collection cycle refreshes the list of projects, collects the projects' resources and then the changes
+	gatherers := prometheus.Gatherers{projects, registry, changes}
+
+	// Inventory mode
+	// A single collection cycle populates the inventory that's then written to stdout
+	if inventoryMode {
+		if _, err := gatherers.Gather(); err != nil {
+			log.Printf("[main] Gather: %v", err)
+		}
+		if err := gcp.WriteResources(os.Stdout, *inventoryFormat, account.Inventory.Resources()); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Push mode
+	// Metrics are pushed to the remote-write endpoint instead of being served
+	if *remoteWriteURL != "" {
+		log.Printf("[main] Pushing metrics to remote-write endpoint (%s)", *remoteWriteURL)
+		client := remotewrite.NewClient(*remoteWriteURL, remoteWriteHeaders, map[string]string{"job": *remoteWriteJob}, *remoteWriteTimeout)
+		if err := client.Run(ctx, gatherers, *remoteWriteInterval); err != nil && err != context.Canceled {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	mux := http.NewServeMux()
 	mux.Handle("/", http.HandlerFunc(handleRoot))
 	mux.Handle("/healthz", http.HandlerFunc(handleHealthz))
-	mux.Handle(*metricsPath, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	mux.Handle("/inventory", handleInventory(account.Inventory))
+	mux.Handle(*metricsPath, promhttp.HandlerFor(gatherers, promhttp.HandlerOpts{}))
 
 	log.Printf("[main] Server starting (%s)", *endpoint)
 	log.Printf("[main] metrics served on: %s", *metricsPath)
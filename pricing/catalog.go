@@ -0,0 +1,184 @@
+package pricing
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"strings"
+
+	"google.golang.org/api/cloudbilling/v1"
+)
+
+const (
+	// computeEngineService is the Cloud Billing Catalog name of the Compute Engine service
+	computeEngineService = "services/6F81-5844-456A"
+	// cloudSQLService is the Cloud Billing Catalog name of the Cloud SQL service
+	cloudSQLService = "services/9662-B51E-5089"
+)
+
+var (
+	// e.g. "E2 Instance Core running in Americas", "N1 Predefined Instance Ram running in Virginia",
+	// "N2D AMD Instance Core running in Americas", "Spot Preemptible E2 Instance Core running in Americas"
+	instanceSku = regexp.MustCompile(`^(Spot Preemptible )?(\w+)(?: AMD)? (?:Predefined )?Instance (Core|Ram) running in `)
+
+	// e.g. "Cloud SQL for MySQL: Zonal - Standard storage in Americas"
+	// Standard storage is SSD and Low cost storage is HDD
+	sqlStorageSku = regexp.MustCompile(`^Cloud SQL for [\w ]+: Zonal - (Standard|Low cost) storage in `)
+	sqlDiskTypes  = map[string]string{
+		"Standard": "PD_SSD",
+		"Low cost": "PD_HDD",
+	}
+
+	// diskSkus maps the descriptions of (zonal) disk SKUs to disk types
+	diskSkus = map[string]string{
+		"Storage PD Capacity":    "pd-standard",
+		"Balanced PD Capacity":   "pd-balanced",
+		"SSD backed PD Capacity": "pd-ssd",
+	}
+)
+
+// Refresh is a method that updates the Table's per-vCPU, per-GB, disk and Cloud SQL storage prices from the Cloud Billing Catalog API
+// Machine type and Cloud SQL tier prices are not refreshed
+func (t *Table) Refresh(ctx context.Context, billingService *cloudbilling.APIService) error {
+	cores := map[string]Prices{}
+	memory := map[string]Prices{}
+	spotCores := map[string]Prices{}
+	spotMemory := map[string]Prices{}
+	disks := map[string]Prices{}
+	sqlDisks := map[string]Prices{}
+
+	set := func(m map[string]Prices, key string, regions []string, price float64) {
+		if _, ok := m[key]; !ok {
+			m[key] = Prices{}
+		}
+		for _, region := range regions {
+			m[key][region] = price
+		}
+	}
+
+	rqst := billingService.Services.Skus.List(computeEngineService).CurrencyCode("USD")
+	if err := rqst.Pages(ctx, func(page *cloudbilling.ListSkusResponse) error {
+		for _, sku := range page.Skus {
+			if sku.Category == nil {
+				continue
+			}
+			// Spot SKUs' usage type is Preemptible
+			spot := sku.Category.UsageType == "Preemptible"
+			if sku.Category.UsageType != "OnDemand" && !spot {
+				continue
+			}
+
+			price, ok := unitPrice(sku)
+			if !ok {
+				continue
+			}
+
+			if family, resource, isSpot, ok := parseInstanceSku(sku.Description); ok && isSpot == spot {
+				switch {
+				case resource == "Core" && spot:
+					set(spotCores, family, sku.ServiceRegions, price)
+				case resource == "Ram" && spot:
+					set(spotMemory, family, sku.ServiceRegions, price)
+				case resource == "Core":
+					set(cores, family, sku.ServiceRegions, price)
+				case resource == "Ram":
+					set(memory, family, sku.ServiceRegions, price)
+				}
+				continue
+			}
+
+			if diskType, ok := diskSkus[sku.Description]; ok && !spot {
+				set(disks, diskType, sku.ServiceRegions, price)
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	rqst = billingService.Services.Skus.List(cloudSQLService).CurrencyCode("USD")
+	if err := rqst.Pages(ctx, func(page *cloudbilling.ListSkusResponse) error {
+		for _, sku := range page.Skus {
+			if sku.Category == nil || sku.Category.UsageType != "OnDemand" {
+				continue
+			}
+
+			price, ok := unitPrice(sku)
+			if !ok {
+				continue
+			}
+
+			if diskType, ok := parseSQLStorageSku(sku.Description); ok {
+				set(sqlDisks, diskType, sku.ServiceRegions, price)
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	log.Printf("[Refresh] Prices for %d core, %d memory, %d Spot core, %d Spot memory, %d disk and %d Cloud SQL disk types",
+		len(cores), len(memory), len(spotCores), len(spotMemory), len(disks), len(sqlDisks))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Refreshed prices replace the table's prices for the same region
+	// Other prices (including the Default) are retained
+	merge := func(dst, src map[string]Prices) {
+		for key, prices := range src {
+			if _, ok := dst[key]; !ok {
+				dst[key] = Prices{}
+			}
+			for region, price := range prices {
+				dst[key][region] = price
+			}
+		}
+	}
+	merge(t.Cores, cores)
+	merge(t.Memory, memory)
+	merge(t.SpotCores, spotCores)
+	merge(t.SpotMemory, spotMemory)
+	merge(t.Disks, disks)
+	merge(t.SQLDisks, sqlDisks)
+
+	return nil
+}
+
+// parseInstanceSku returns the (lowercase) machine family, resource (Core or Ram) and whether Spot of an instance SKU's description
+func parseInstanceSku(description string) (family, resource string, spot, ok bool) {
+	m := instanceSku.FindStringSubmatch(description)
+	if m == nil {
+		return "", "", false, false
+	}
+	return strings.ToLower(m[2]), m[3], m[1] != "", true
+}
+
+// parseSQLStorageSku returns the data disk type (PD_SSD or PD_HDD) of a zonal Cloud SQL storage SKU's description
+func parseSQLStorageSku(description string) (string, bool) {
+	m := sqlStorageSku.FindStringSubmatch(description)
+	if m == nil {
+		return "", false
+	}
+	diskType, ok := sqlDiskTypes[m[1]]
+	return diskType, ok
+}
+
+// unitPrice returns the first non-zero (tiered) unit price of a SKU
+func unitPrice(sku *cloudbilling.Sku) (float64, bool) {
+	for _, info := range sku.PricingInfo {
+		if info.PricingExpression == nil {
+			continue
+		}
+		for _, rate := range info.PricingExpression.TieredRates {
+			if rate.UnitPrice == nil {
+				continue
+			}
+			price := float64(rate.UnitPrice.Units) + float64(rate.UnitPrice.Nanos)/1e9
+			if price > 0 {
+				return price, true
+			}
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,117 @@
+package pricing
+
+import (
+	"testing"
+
+	"google.golang.org/api/cloudbilling/v1"
+)
+
+func TestParseInstanceSku(t *testing.T) {
+	tests := []struct {
+		description string
+		family      string
+		resource    string
+		spot        bool
+		ok          bool
+	}{
+		{"E2 Instance Core running in Americas", "e2", "Core", false, true},
+		{"E2 Instance Ram running in Americas", "e2", "Ram", false, true},
+		{"N1 Predefined Instance Core running in Virginia", "n1", "Core", false, true},
+		{"N1 Predefined Instance Ram running in Virginia", "n1", "Ram", false, true},
+		{"N2D AMD Instance Core running in Americas", "n2d", "Core", false, true},
+		{"N2D AMD Instance Ram running in Belgium", "n2d", "Ram", false, true},
+		{"C2D AMD Instance Core running in Americas", "c2d", "Core", false, true},
+		{"Spot Preemptible E2 Instance Core running in Americas", "e2", "Core", true, true},
+		{"Spot Preemptible N2D AMD Instance Ram running in Belgium", "n2d", "Ram", true, true},
+		{"N2 Custom Instance Core running in Americas", "", "", false, false},
+		{"Storage PD Capacity", "", "", false, false},
+		{"", "", "", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			family, resource, spot, ok := parseInstanceSku(tt.description)
+			if family != tt.family || resource != tt.resource || spot != tt.spot || ok != tt.ok {
+				t.Errorf("got (%q, %q, %t, %t), want (%q, %q, %t, %t)", family, resource, spot, ok, tt.family, tt.resource, tt.spot, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParseSQLStorageSku(t *testing.T) {
+	tests := []struct {
+		description string
+		diskType    string
+		ok          bool
+	}{
+		{"Cloud SQL for MySQL: Zonal - Standard storage in Americas", "PD_SSD", true},
+		{"Cloud SQL for PostgreSQL: Zonal - Low cost storage in Belgium", "PD_HDD", true},
+		{"Cloud SQL for SQL Server: Zonal - Standard storage in Iowa", "PD_SSD", true},
+		{"Cloud SQL for MySQL: Regional - Standard storage in Americas", "", false},
+		{"Cloud SQL for MySQL: Zonal - Backups in Americas", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			diskType, ok := parseSQLStorageSku(tt.description)
+			if diskType != tt.diskType || ok != tt.ok {
+				t.Errorf("got (%q, %t), want (%q, %t)", diskType, ok, tt.diskType, tt.ok)
+			}
+		})
+	}
+}
+
+func TestUnitPrice(t *testing.T) {
+	rates := func(rates ...*cloudbilling.TierRate) *cloudbilling.Sku {
+		return &cloudbilling.Sku{
+			PricingInfo: []*cloudbilling.PricingInfo{
+				{
+					PricingExpression: &cloudbilling.PricingExpression{
+						TieredRates: rates,
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		sku   *cloudbilling.Sku
+		price float64
+		ok    bool
+	}{
+		{
+			name:  "units and nanos",
+			sku:   rates(&cloudbilling.TierRate{UnitPrice: &cloudbilling.Money{Units: 1, Nanos: 500000000}}),
+			price: 1.5,
+			ok:    true,
+		},
+		{
+			name: "first non-zero tier",
+			sku: rates(
+				&cloudbilling.TierRate{UnitPrice: &cloudbilling.Money{}},
+				&cloudbilling.TierRate{UnitPrice: &cloudbilling.Money{Nanos: 40000000}},
+			),
+			price: 0.04,
+			ok:    true,
+		},
+		{
+			name: "free",
+			sku:  rates(&cloudbilling.TierRate{UnitPrice: &cloudbilling.Money{}}),
+		},
+		{
+			name: "no pricing",
+			sku:  &cloudbilling.Sku{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, ok := unitPrice(tt.sku)
+			if price != tt.price || ok != tt.ok {
+				t.Errorf("got (%v, %t), want (%v, %t)", price, ok, tt.price, tt.ok)
+			}
+		})
+	}
+}
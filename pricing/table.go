@@ -0,0 +1,282 @@
+package pricing
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// Default is the region key that matches any region
+	Default = "*"
+
+	// hoursPerMonth is used to convert monthly (e.g. disk) prices to hourly prices
+	hoursPerMonth = 730
+)
+
+// Prices represents prices by region
+// The Default ("*") region is used when a region is not listed
+type Prices map[string]float64
+
+// Price is a method that returns the price in a region (or the default price)
+func (p Prices) Price(region string) (float64, bool) {
+	if price, ok := p[region]; ok {
+		return price, true
+	}
+	price, ok := p[Default]
+	return price, ok
+}
+
+// Table represents a table of (USD) prices
+type Table struct {
+	mu sync.RWMutex
+
+	// MachineTypes are hourly prices by machine type (e.g. e2-standard-4)
+	MachineTypes map[string]Prices `json:"machine_types" yaml:"machine_types"`
+	// Cores are hourly prices per vCPU by machine family (e.g. e2)
+	// Cores and Memory are used for machine types that are not listed in MachineTypes
+	Cores map[string]Prices `json:"cores" yaml:"cores"`
+	// Memory are hourly prices per GB by machine family (e.g. e2)
+	Memory map[string]Prices `json:"memory" yaml:"memory"`
+	// SpotCores are hourly Spot (and preemptible) prices per vCPU by machine family (e.g. e2)
+	SpotCores map[string]Prices `json:"spot_cores" yaml:"spot_cores"`
+	// SpotMemory are hourly Spot (and preemptible) prices per GB by machine family (e.g. e2)
+	SpotMemory map[string]Prices `json:"spot_memory" yaml:"spot_memory"`
+	// Disks are monthly prices per GB by disk type (e.g. pd-balanced)
+	Disks map[string]Prices `json:"disks" yaml:"disks"`
+	// SQLTiers are hourly prices by Cloud SQL tier (e.g. db-f1-micro)
+	SQLTiers map[string]Prices `json:"sql_tiers" yaml:"sql_tiers"`
+	// SQLDisks are monthly prices per GB of zonal Cloud SQL storage by data disk type (PD_SSD or PD_HDD)
+	SQLDisks map[string]Prices `json:"sql_disks" yaml:"sql_disks"`
+	// GKEClusters are hourly prices of the GKE cluster management fee
+	GKEClusters Prices `json:"gke_clusters" yaml:"gke_clusters"`
+}
+
+// NewTable returns a new empty Table
+func NewTable() *Table {
+	return &Table{
+		MachineTypes: map[string]Prices{},
+		Cores:        map[string]Prices{},
+		Memory:       map[string]Prices{},
+		SpotCores:    map[string]Prices{},
+		SpotMemory:   map[string]Prices{},
+		Disks:        map[string]Prices{},
+		SQLTiers:     map[string]Prices{},
+		SQLDisks:     map[string]Prices{},
+		GKEClusters:  Prices{},
+	}
+}
+
+// Load returns a Table from a YAML or JSON file
+// The format is determined by the file's extension
+func Load(path string) (*Table, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	t := NewTable()
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(b, t)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, t)
+	default:
+		return nil, fmt.Errorf("unsupported price table format '%s' (expected .json, .yaml or .yml)", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshaling may replace the (empty) maps with nil
+	if t.MachineTypes == nil {
+		t.MachineTypes = map[string]Prices{}
+	}
+	if t.Cores == nil {
+		t.Cores = map[string]Prices{}
+	}
+	if t.Memory == nil {
+		t.Memory = map[string]Prices{}
+	}
+	if t.SpotCores == nil {
+		t.SpotCores = map[string]Prices{}
+	}
+	if t.SpotMemory == nil {
+		t.SpotMemory = map[string]Prices{}
+	}
+	if t.Disks == nil {
+		t.Disks = map[string]Prices{}
+	}
+	if t.SQLTiers == nil {
+		t.SQLTiers = map[string]Prices{}
+	}
+	if t.SQLDisks == nil {
+		t.SQLDisks = map[string]Prices{}
+	}
+	if t.GKEClusters == nil {
+		t.GKEClusters = Prices{}
+	}
+
+	return t, nil
+}
+
+// MachineType is a method that returns the hourly price of a machine type in a region
+// Machine types that are not listed are priced by their family's per-vCPU and per-GB prices
+func (t *Table) MachineType(machineType, region string) (float64, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if prices, ok := t.MachineTypes[machineType]; ok {
+		if price, ok := prices.Price(region); ok {
+			return price, true
+		}
+	}
+
+	return shapePrice(t.Cores, t.Memory, machineType, region)
+}
+
+// SpotMachineType is a method that returns the hourly Spot (or preemptible) price of a machine type in a region
+// Machine types are priced by their family's Spot per-vCPU and per-GB prices
+func (t *Table) SpotMachineType(machineType, region string) (float64, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return shapePrice(t.SpotCores, t.SpotMemory, machineType, region)
+}
+
+// shapePrice returns the hourly price of a machine type from per-vCPU and per-GB prices by machine family
+func shapePrice(cores, memory map[string]Prices, machineType, region string) (float64, bool) {
+	family, vCPUs, memoryGB, ok := Shape(machineType)
+	if !ok {
+		return 0, false
+	}
+
+	core, ok := cores[family].Price(region)
+	if !ok {
+		return 0, false
+	}
+	gb, ok := memory[family].Price(region)
+	if !ok {
+		return 0, false
+	}
+
+	return vCPUs*core + memoryGB*gb, true
+}
+
+// Disk is a method that returns the hourly price of a disk of a type and size in a region
+func (t *Table) Disk(diskType, region string, sizeGB int64) (float64, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	price, ok := t.Disks[diskType].Price(region)
+	if !ok {
+		return 0, false
+	}
+	return price * float64(sizeGB) / hoursPerMonth, true
+}
+
+// SQLTier is a method that returns the hourly price of a Cloud SQL tier in a region
+func (t *Table) SQLTier(tier, region string) (float64, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.SQLTiers[tier].Price(region)
+}
+
+// SQLDisk is a method that returns the hourly price of Cloud SQL storage of a data disk type and size in a region
+// Regional (high availability) instances' storage is replicated and so is priced at twice the zonal price
+func (t *Table) SQLDisk(diskType, region string, sizeGB int64, regional bool) (float64, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	price, ok := t.SQLDisks[diskType].Price(region)
+	if !ok {
+		return 0, false
+	}
+	if regional {
+		price *= 2
+	}
+	return price * float64(sizeGB) / hoursPerMonth, true
+}
+
+// GKECluster is a method that returns the hourly price of the GKE cluster management fee in a region
+func (t *Table) GKECluster(region string) (float64, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.GKEClusters.Price(region)
+}
+
+// sharedCore are the vCPUs and memory (GB) of shared-core machine types
+var sharedCore = map[string][2]float64{
+	"e2-micro":  {0.25, 1},
+	"e2-small":  {0.5, 2},
+	"e2-medium": {1, 4},
+	"f1-micro":  {0.2, 0.6},
+	"g1-small":  {0.5, 1.7},
+}
+
+// Shape returns the family, number of vCPUs and memory (GB) of a machine type
+// e.g. e2-standard-4, n1-highmem-8, n2-custom-4-8192, custom-2-4096 (N1)
+func Shape(machineType string) (family string, vCPUs, memoryGB float64, ok bool) {
+	if s, ok := sharedCore[machineType]; ok {
+		return strings.SplitN(machineType, "-", 2)[0], s[0], s[1], true
+	}
+
+	parts := strings.Split(machineType, "-")
+
+	// Custom machine types are {family}-custom-{vCPUs}-{memoryMB} or custom-{vCPUs}-{memoryMB} (N1)
+	if len(parts) == 3 && parts[0] == "custom" {
+		parts = append([]string{"n1"}, parts...)
+	}
+	if len(parts) >= 4 && parts[1] == "custom" {
+		cpus, err := strconv.ParseFloat(parts[2], 64)
+		if err != nil {
+			return "", 0, 0, false
+		}
+		memoryMB, err := strconv.ParseFloat(parts[3], 64)
+		if err != nil {
+			return "", 0, 0, false
+		}
+		return parts[0], cpus, memoryMB / 1024, true
+	}
+
+	// Predefined machine types are {family}-{class}-{vCPUs}
+	if len(parts) != 3 {
+		return "", 0, 0, false
+	}
+	cpus, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return "", 0, 0, false
+	}
+
+	family = parts[0]
+	var perCPU float64
+	switch parts[1] {
+	case "standard":
+		perCPU = 4
+		if family == "n1" {
+			perCPU = 3.75
+		}
+	case "highmem":
+		perCPU = 8
+		if family == "n1" {
+			perCPU = 6.5
+		}
+	case "highcpu":
+		perCPU = 1
+		if family == "n1" {
+			perCPU = 0.9
+		}
+	default:
+		return "", 0, 0, false
+	}
+
+	return family, cpus, cpus * perCPU, true
+}
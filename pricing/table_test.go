@@ -0,0 +1,85 @@
+package pricing
+
+import (
+	"math"
+	"testing"
+)
+
+func TestShape(t *testing.T) {
+	tests := []struct {
+		machineType string
+		family      string
+		vCPUs       float64
+		memoryGB    float64
+		ok          bool
+	}{
+		{"e2-micro", "e2", 0.25, 1, true},
+		{"f1-micro", "f1", 0.2, 0.6, true},
+		{"e2-standard-4", "e2", 4, 16, true},
+		{"n1-standard-2", "n1", 2, 7.5, true},
+		{"n1-highmem-8", "n1", 8, 52, true},
+		{"n1-highcpu-16", "n1", 16, 14.4, true},
+		{"n2-highmem-4", "n2", 4, 32, true},
+		{"n2d-highcpu-8", "n2d", 8, 8, true},
+		{"n2-custom-4-8192", "n2", 4, 8, true},
+		{"n2-custom-2-16384-ext", "n2", 2, 16, true},
+		{"custom-2-4096", "n1", 2, 4, true},
+		{"a2-ultragpu-1g", "", 0, 0, false},
+		{"n2-standard-x", "", 0, 0, false},
+		{"n2-custom-x-8192", "", 0, 0, false},
+		{"e2", "", 0, 0, false},
+		{"", "", 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.machineType, func(t *testing.T) {
+			family, vCPUs, memoryGB, ok := Shape(tt.machineType)
+			if family != tt.family || vCPUs != tt.vCPUs || memoryGB != tt.memoryGB || ok != tt.ok {
+				t.Errorf("got (%q, %v, %v, %t), want (%q, %v, %v, %t)", family, vCPUs, memoryGB, ok, tt.family, tt.vCPUs, tt.memoryGB, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSpotMachineType(t *testing.T) {
+	table := NewTable()
+	table.Cores["e2"] = Prices{Default: 0.02}
+	table.Memory["e2"] = Prices{Default: 0.003}
+	table.SpotCores["e2"] = Prices{Default: 0.01}
+	table.SpotMemory["e2"] = Prices{Default: 0.001}
+
+	price, ok := table.SpotMachineType("e2-standard-2", "us-west1")
+	if want := 2*0.01 + 8*0.001; !ok || price != want {
+		t.Errorf("got (%v, %t), want (%v, true)", price, ok, want)
+	}
+
+	if _, ok := table.SpotMachineType("n2-standard-2", "us-west1"); ok {
+		t.Error("expected no Spot price for a family without Spot prices")
+	}
+}
+
+func TestSQLDisk(t *testing.T) {
+	table := NewTable()
+	table.SQLDisks["PD_SSD"] = Prices{Default: 0.17 * hoursPerMonth}
+
+	tests := []struct {
+		name     string
+		diskType string
+		regional bool
+		price    float64
+		ok       bool
+	}{
+		{"zonal", "PD_SSD", false, 0.17 * 10, true},
+		{"regional", "PD_SSD", true, 2 * 0.17 * 10, true},
+		{"no price", "PD_HDD", false, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, ok := table.SQLDisk(tt.diskType, "us-west1", 10, tt.regional)
+			if math.Abs(price-tt.price) > 1e-9 || ok != tt.ok {
+				t.Errorf("got (%v, %t), want (%v, %t)", price, ok, tt.price, tt.ok)
+			}
+		})
+	}
+}
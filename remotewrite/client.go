@@ -0,0 +1,139 @@
+package remotewrite
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	userAgent = "gcp-exporter"
+	version   = "0.1.0"
+)
+
+// Headers is a repeatable flag of HTTP headers of the form "Name: Value"
+// Headers are used to authenticate to the remote-write endpoint e.g. "Authorization: Bearer ..."
+type Headers map[string]string
+
+// String implements flag.Value
+func (h Headers) String() string {
+	hh := []string{}
+	for k := range h {
+		// Values are omitted as these are likely (!) credentials
+		hh = append(hh, k)
+	}
+	return strings.Join(hh, ",")
+}
+
+// Set implements flag.Value
+func (h Headers) Set(value string) error {
+	k, v, ok := strings.Cut(value, ":")
+	if !ok {
+		return fmt.Errorf("expected header of the form 'Name: Value', got '%s'", value)
+	}
+	h[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	return nil
+}
+
+// Client pushes metrics to a Prometheus remote-write endpoint
+type Client struct {
+	url     string
+	headers Headers
+	labels  []Label
+
+	client *http.Client
+}
+
+// NewClient returns a new Client
+// labels are added to every time series (e.g. job) as the Prometheus server would when scraping
+func NewClient(url string, headers Headers, labels map[string]string, timeout time.Duration) *Client {
+	ll := []Label{}
+	for name, value := range labels {
+		ll = append(ll, Label{Name: name, Value: value})
+	}
+
+	return &Client{
+		url:     url,
+		headers: headers,
+		labels:  ll,
+
+		client: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// Push gathers metrics once and writes them to the remote-write endpoint
+func (c *Client) Push(ctx context.Context, gatherer prometheus.Gatherer) error {
+	mfs, err := gatherer.Gather()
+	if err != nil {
+		// Gather returns as many metrics as possible, even when it errors
+		log.Printf("[remotewrite] Gather: %v", err)
+	}
+
+	series := FromMetricFamilies(mfs, c.labels, time.Now().UnixMilli())
+	log.Printf("[remotewrite] Pushing %d time series", len(series))
+	if len(series) == 0 {
+		return nil
+	}
+
+	body := snappy.Encode(nil, Marshal(series))
+
+	rqst, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	for k, v := range c.headers {
+		rqst.Header.Set(k, v)
+	}
+	rqst.Header.Set("Content-Encoding", "snappy")
+	rqst.Header.Set("Content-Type", "application/x-protobuf")
+	rqst.Header.Set("User-Agent", userAgent)
+	rqst.Header.Set("X-Prometheus-Remote-Write-Version", version)
+
+	resp, err := c.client.Do(rqst)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("remote-write endpoint returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	return nil
+}
+
+// Run pushes metrics every interval until the context is cancelled
+// If interval is zero, metrics are pushed once
+func (c *Client) Run(ctx context.Context, gatherer prometheus.Gatherer, interval time.Duration) error {
+	if interval <= 0 {
+		return c.Push(ctx, gatherer)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.Push(ctx, gatherer); err != nil {
+			// Continue on errors; the next push may succeed
+			log.Printf("[remotewrite] Push: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package remotewrite
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestPush(t *testing.T) {
+	type request struct {
+		headers http.Header
+		series  []TimeSeries
+		err     error
+	}
+	requests := make(chan request, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rqst := request{headers: r.Header.Clone()}
+		defer func() { requests <- rqst }()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			rqst.err = err
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		b, err := snappy.Decode(nil, body)
+		if err != nil {
+			rqst.err = err
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		rqst.series, rqst.err = unmarshal(b)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	registry := prometheus.NewRegistry()
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gcp_test_gauge",
+		Help: "Test gauge",
+	}, []string{"project"})
+	gauge.WithLabelValues("p").Set(2)
+	registry.MustRegister(gauge)
+
+	headers := Headers{}
+	if err := headers.Set("Authorization: Bearer token"); err != nil {
+		t.Fatal(err)
+	}
+	client := NewClient(server.URL, headers, map[string]string{"job": "gcp-exporter"}, 5*time.Second)
+
+	if err := client.Push(context.Background(), registry); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+
+	rqst := <-requests
+	if rqst.err != nil {
+		t.Fatalf("receiver: %v", rqst.err)
+	}
+
+	for k, want := range map[string]string{
+		"Authorization":                     "Bearer token",
+		"Content-Encoding":                  "snappy",
+		"Content-Type":                      "application/x-protobuf",
+		"X-Prometheus-Remote-Write-Version": version,
+	} {
+		if got := rqst.headers.Get(k); got != want {
+			t.Errorf("header %s: got %q, want %q", k, got, want)
+		}
+	}
+
+	if len(rqst.series) != 1 {
+		t.Fatalf("got %d series, want 1: %+v", len(rqst.series), rqst.series)
+	}
+	s := rqst.series[0]
+
+	wantLabels := []Label{
+		{Name: "__name__", Value: "gcp_test_gauge"},
+		{Name: "job", Value: "gcp-exporter"},
+		{Name: "project", Value: "p"},
+	}
+	if fmt.Sprint(s.Labels) != fmt.Sprint(wantLabels) {
+		t.Errorf("labels: got %v, want %v", s.Labels, wantLabels)
+	}
+	if len(s.Samples) != 1 || s.Samples[0].Value != 2 {
+		t.Errorf("samples: got %+v, want a single sample with value 2", s.Samples)
+	}
+}
+
+func TestPushError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "out of order sample", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "gcp_test_total",
+		Help: "Test counter",
+	}))
+
+	client := NewClient(server.URL, Headers{}, nil, 5*time.Second)
+	if err := client.Push(context.Background(), registry); err == nil {
+		t.Error("expected an error when the receiver rejects the request")
+	}
+}
+
+// unmarshal decodes a (protobuf) prometheus.WriteRequest as the receiver would
+func unmarshal(b []byte) ([]TimeSeries, error) {
+	series := []TimeSeries{}
+	err := fields(b, func(num protowire.Number, v []byte) error {
+		if num != 1 {
+			return nil
+		}
+		s := TimeSeries{}
+		if err := fields(v, func(num protowire.Number, v []byte) error {
+			switch num {
+			case 1:
+				l := Label{}
+				if err := fields(v, func(num protowire.Number, v []byte) error {
+					switch num {
+					case 1:
+						l.Name = string(v)
+					case 2:
+						l.Value = string(v)
+					}
+					return nil
+				}); err != nil {
+					return err
+				}
+				s.Labels = append(s.Labels, l)
+			case 2:
+				x, err := unmarshalSample(v)
+				if err != nil {
+					return err
+				}
+				s.Samples = append(s.Samples, x)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		series = append(series, s)
+		return nil
+	})
+	return series, err
+}
+
+// fields calls f with each length-delimited field of a message
+func fields(b []byte, f func(protowire.Number, []byte) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		if typ != protowire.BytesType {
+			return fmt.Errorf("field %d: unexpected wire type %d", num, typ)
+		}
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		if err := f(num, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unmarshalSample decodes a Sample message
+func unmarshalSample(b []byte) (Sample, error) {
+	x := Sample{}
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return x, protowire.ParseError(n)
+		}
+		b = b[n:]
+		switch {
+		case num == 1 && typ == protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(b)
+			if n < 0 {
+				return x, protowire.ParseError(n)
+			}
+			x.Value = math.Float64frombits(v)
+			b = b[n:]
+		case num == 2 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return x, protowire.ParseError(n)
+			}
+			x.Timestamp = int64(v)
+			b = b[n:]
+		default:
+			return x, fmt.Errorf("sample field %d: unexpected wire type %d", num, typ)
+		}
+	}
+	return x, nil
+}
@@ -0,0 +1,157 @@
+package remotewrite
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Label is a Prometheus remote-write label (name-value pair)
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is a Prometheus remote-write sample
+type Sample struct {
+	Value     float64
+	Timestamp int64
+}
+
+// TimeSeries is a Prometheus remote-write time series
+// A series comprises a set of labels (including __name__) and its samples
+type TimeSeries struct {
+	Labels  []Label
+	Samples []Sample
+}
+
+// FromMetricFamilies converts the output of a Prometheus Gatherer into remote-write time series
+// Metrics without a timestamp are assigned the provided timestamp (milliseconds since the Unix epoch)
+func FromMetricFamilies(mfs []*dto.MetricFamily, external []Label, timestamp int64) []TimeSeries {
+	series := []TimeSeries{}
+	for _, mf := range mfs {
+		name := mf.GetName()
+		for _, m := range mf.GetMetric() {
+			ts := timestamp
+			if m.TimestampMs != nil {
+				ts = m.GetTimestampMs()
+			}
+
+			labels := make([]Label, 0, len(m.GetLabel())+len(external))
+			for _, l := range m.GetLabel() {
+				labels = append(labels, Label{Name: l.GetName(), Value: l.GetValue()})
+			}
+			labels = append(labels, external...)
+
+			add := func(name string, value float64, extra ...Label) {
+				series = append(series, newTimeSeries(name, labels, extra, value, ts))
+			}
+
+			switch mf.GetType() {
+			case dto.MetricType_COUNTER:
+				add(name, m.GetCounter().GetValue())
+			case dto.MetricType_GAUGE:
+				add(name, m.GetGauge().GetValue())
+			case dto.MetricType_UNTYPED:
+				add(name, m.GetUntyped().GetValue())
+			case dto.MetricType_SUMMARY:
+				s := m.GetSummary()
+				for _, q := range s.GetQuantile() {
+					add(name, q.GetValue(), Label{Name: "quantile", Value: formatFloat(q.GetQuantile())})
+				}
+				add(name+"_sum", s.GetSampleSum())
+				add(name+"_count", float64(s.GetSampleCount()))
+			case dto.MetricType_HISTOGRAM:
+				h := m.GetHistogram()
+				for _, b := range h.GetBucket() {
+					add(name+"_bucket", float64(b.GetCumulativeCount()), Label{Name: "le", Value: formatFloat(b.GetUpperBound())})
+				}
+				add(name+"_bucket", float64(h.GetSampleCount()), Label{Name: "le", Value: "+Inf"})
+				add(name+"_sum", h.GetSampleSum())
+				add(name+"_count", float64(h.GetSampleCount()))
+			}
+		}
+	}
+	return series
+}
+
+// newTimeSeries creates a single-sample time series
+// Remote-write requires that labels be sorted by name
+func newTimeSeries(name string, labels, extra []Label, value float64, timestamp int64) TimeSeries {
+	ll := make([]Label, 0, len(labels)+len(extra)+1)
+	ll = append(ll, Label{Name: "__name__", Value: name})
+	ll = append(ll, labels...)
+	ll = append(ll, extra...)
+	sort.Slice(ll, func(i, j int) bool {
+		return ll[i].Name < ll[j].Name
+	})
+
+	return TimeSeries{
+		Labels: ll,
+		Samples: []Sample{
+			{
+				Value:     value,
+				Timestamp: timestamp,
+			},
+		},
+	}
+}
+
+// formatFloat formats a float as Prometheus does for quantile and le labels
+func formatFloat(f float64) string {
+	if math.IsInf(f, +1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// Marshal encodes the time series as a (protobuf) prometheus.WriteRequest
+// https://github.com/prometheus/prometheus/blob/main/prompb/remote.proto
+func Marshal(series []TimeSeries) []byte {
+	// WriteRequest
+	// 1: repeated TimeSeries timeseries
+	b := []byte{}
+	for _, s := range series {
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendBytes(b, marshalTimeSeries(s))
+	}
+	return b
+}
+
+// marshalTimeSeries encodes a TimeSeries message
+func marshalTimeSeries(s TimeSeries) []byte {
+	// TimeSeries
+	// 1: repeated Label labels
+	// 2: repeated Sample samples
+	b := []byte{}
+	for _, l := range s.Labels {
+		// Label
+		// 1: string name
+		// 2: string value
+		m := []byte{}
+		m = protowire.AppendTag(m, 1, protowire.BytesType)
+		m = protowire.AppendString(m, l.Name)
+		m = protowire.AppendTag(m, 2, protowire.BytesType)
+		m = protowire.AppendString(m, l.Value)
+
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendBytes(b, m)
+	}
+	for _, x := range s.Samples {
+		// Sample
+		// 1: double value
+		// 2: int64 timestamp
+		m := []byte{}
+		m = protowire.AppendTag(m, 1, protowire.Fixed64Type)
+		m = protowire.AppendFixed64(m, math.Float64bits(x.Value))
+		m = protowire.AppendTag(m, 2, protowire.VarintType)
+		m = protowire.AppendVarint(m, uint64(x.Timestamp))
+
+		b = protowire.AppendTag(b, 2, protowire.BytesType)
+		b = protowire.AppendBytes(b, m)
+	}
+	return b
+}
@@ -0,0 +1,129 @@
+package remotewrite
+
+import (
+	"reflect"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestFromMetricFamilies(t *testing.T) {
+	const timestamp int64 = 1000
+
+	external := []Label{
+		{Name: "job", Value: "gcp-exporter"},
+	}
+	labels := []*dto.LabelPair{
+		{Name: proto.String("project"), Value: proto.String("p")},
+	}
+
+	tests := []struct {
+		name string
+		mf   *dto.MetricFamily
+		want []TimeSeries
+	}{
+		{
+			name: "counter",
+			mf: &dto.MetricFamily{
+				Name: proto.String("c_total"),
+				Type: dto.MetricType_COUNTER.Enum(),
+				Metric: []*dto.Metric{
+					{Label: labels, Counter: &dto.Counter{Value: proto.Float64(3)}},
+				},
+			},
+			want: []TimeSeries{
+				series(timestamp, 3, "__name__", "c_total", "job", "gcp-exporter", "project", "p"),
+			},
+		},
+		{
+			name: "gauge with timestamp",
+			mf: &dto.MetricFamily{
+				Name: proto.String("g"),
+				Type: dto.MetricType_GAUGE.Enum(),
+				Metric: []*dto.Metric{
+					{Label: labels, Gauge: &dto.Gauge{Value: proto.Float64(1.5)}, TimestampMs: proto.Int64(42)},
+				},
+			},
+			want: []TimeSeries{
+				series(42, 1.5, "__name__", "g", "job", "gcp-exporter", "project", "p"),
+			},
+		},
+		{
+			name: "histogram",
+			mf: &dto.MetricFamily{
+				Name: proto.String("h"),
+				Type: dto.MetricType_HISTOGRAM.Enum(),
+				Metric: []*dto.Metric{
+					{
+						Label: labels,
+						Histogram: &dto.Histogram{
+							SampleCount: proto.Uint64(5),
+							SampleSum:   proto.Float64(7),
+							Bucket: []*dto.Bucket{
+								{UpperBound: proto.Float64(0.5), CumulativeCount: proto.Uint64(2)},
+								{UpperBound: proto.Float64(1), CumulativeCount: proto.Uint64(4)},
+							},
+						},
+					},
+				},
+			},
+			want: []TimeSeries{
+				series(timestamp, 2, "__name__", "h_bucket", "job", "gcp-exporter", "le", "0.5", "project", "p"),
+				series(timestamp, 4, "__name__", "h_bucket", "job", "gcp-exporter", "le", "1", "project", "p"),
+				series(timestamp, 5, "__name__", "h_bucket", "job", "gcp-exporter", "le", "+Inf", "project", "p"),
+				series(timestamp, 7, "__name__", "h_sum", "job", "gcp-exporter", "project", "p"),
+				series(timestamp, 5, "__name__", "h_count", "job", "gcp-exporter", "project", "p"),
+			},
+		},
+		{
+			name: "summary",
+			mf: &dto.MetricFamily{
+				Name: proto.String("s"),
+				Type: dto.MetricType_SUMMARY.Enum(),
+				Metric: []*dto.Metric{
+					{
+						Label: labels,
+						Summary: &dto.Summary{
+							SampleCount: proto.Uint64(10),
+							SampleSum:   proto.Float64(20),
+							Quantile: []*dto.Quantile{
+								{Quantile: proto.Float64(0.5), Value: proto.Float64(2)},
+								{Quantile: proto.Float64(0.99), Value: proto.Float64(9)},
+							},
+						},
+					},
+				},
+			},
+			want: []TimeSeries{
+				series(timestamp, 2, "__name__", "s", "job", "gcp-exporter", "project", "p", "quantile", "0.5"),
+				series(timestamp, 9, "__name__", "s", "job", "gcp-exporter", "project", "p", "quantile", "0.99"),
+				series(timestamp, 20, "__name__", "s_sum", "job", "gcp-exporter", "project", "p"),
+				series(timestamp, 10, "__name__", "s_count", "job", "gcp-exporter", "project", "p"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FromMetricFamilies([]*dto.MetricFamily{tt.mf}, external, timestamp)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got:\n%+v\nwant:\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// series returns a single-sample time series from label name-value pairs
+func series(timestamp int64, value float64, pairs ...string) TimeSeries {
+	labels := []Label{}
+	for i := 0; i < len(pairs); i += 2 {
+		labels = append(labels, Label{Name: pairs[i], Value: pairs[i+1]})
+	}
+	return TimeSeries{
+		Labels: labels,
+		Samples: []Sample{
+			{Value: value, Timestamp: timestamp},
+		},
+	}
+}
@@ -0,0 +1,229 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DazWilkin/gcp-exporter/gcp"
+)
+
+// Formats are the payload formats supported by the Notifier
+var Formats = []string{"generic", "slack"}
+
+// URLs is a repeatable flag of webhook URLs
+type URLs []string
+
+// String implements flag.Value
+func (u *URLs) String() string {
+	return strings.Join(*u, ",")
+}
+
+// Set implements flag.Value
+func (u *URLs) Set(value string) error {
+	*u = append(*u, value)
+	return nil
+}
+
+// Payload is the body of a generic webhook notification
+type Payload struct {
+	Time      time.Time         `json:"time"`
+	Action    string            `json:"action"`
+	Project   string            `json:"project"`
+	Service   string            `json:"service"`
+	Type      string            `json:"type"`
+	Location  string            `json:"location"`
+	Resource  string            `json:"resource"`
+	State     string            `json:"state"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	FirstSeen time.Time         `json:"first_seen"`
+}
+
+// NewPayload converts a Change into a Payload
+func NewPayload(change gcp.Change) Payload {
+	r := change.Resource
+	return Payload{
+		Time:      change.Time,
+		Action:    change.Action,
+		Project:   r.Project,
+		Service:   r.Service,
+		Type:      r.Type,
+		Location:  r.Location,
+		Resource:  r.Name,
+		State:     r.State,
+		Labels:    r.Labels,
+		FirstSeen: r.FirstSeen,
+	}
+}
+
+// SlackPayload is the body of a Slack-compatible (incoming webhook) notification
+type SlackPayload struct {
+	Text string `json:"text"`
+}
+
+// NewSlackPayload converts a Change into a SlackPayload
+func NewSlackPayload(change gcp.Change) SlackPayload {
+	r := change.Resource
+	return SlackPayload{
+		Text: fmt.Sprintf("GCP %s %s `%s` %s (project: %s, location: %s, first seen: %s)",
+			r.Service, r.Type, r.Name, change.Action, r.Project, r.Location, r.FirstSeen.Format(time.RFC3339)),
+	}
+}
+
+// Notifier posts Changes to webhooks
+// Notifications are deduplicated and rate-limited
+type Notifier struct {
+	urls     []string
+	format   string
+	dryRun   bool
+	interval time.Duration
+	window   time.Duration
+
+	client *http.Client
+	queue  chan gcp.Change
+
+	mu   sync.Mutex
+	sent map[string]time.Time
+}
+
+// NewNotifier returns a new Notifier
+// interval is the minimum time between requests to the webhooks
+// window is the time within which duplicate notifications are suppressed
+// queueSize is the number of notifications that may be pending; when the queue is full, notifications are dropped
+// If dryRun is true, notifications are logged rather than posted
+func NewNotifier(urls []string, format string, dryRun bool, interval, window, timeout time.Duration, queueSize int) (*Notifier, error) {
+	switch format {
+	case "generic", "slack":
+	default:
+		return nil, fmt.Errorf("unsupported webhook format '%s' (expected one of %v)", format, Formats)
+	}
+	if queueSize < 1 {
+		return nil, fmt.Errorf("webhook queue size must be at least 1 (got %d)", queueSize)
+	}
+
+	return &Notifier{
+		urls:     urls,
+		format:   format,
+		dryRun:   dryRun,
+		interval: interval,
+		window:   window,
+
+		client: &http.Client{
+			Timeout: timeout,
+		},
+		queue: make(chan gcp.Change, queueSize),
+
+		sent: map[string]time.Time{},
+	}, nil
+}
+
+// Notify is a method that queues a Change to be posted
+// It does not block and may be used as an Inventory subscriber
+// Changes are recorded as notified only once they're queued so that dropped changes aren't suppressed as duplicates
+func (n *Notifier) Notify(change gcp.Change) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.duplicate(change) {
+		log.Printf("[webhook] Suppressing duplicate notification: %s", key(change))
+		return
+	}
+
+	select {
+	case n.queue <- change:
+		n.sent[key(change)] = change.Time
+	default:
+		log.Printf("[webhook] Queue is full; dropping notification: %s", key(change))
+	}
+}
+
+// Run is a method that posts queued notifications until the context is cancelled
+// Requests are made no more often than the Notifier's interval
+func (n *Notifier) Run(ctx context.Context) {
+	var last time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case change := <-n.queue:
+			if wait := n.interval - time.Since(last); wait > 0 {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(wait):
+				}
+			}
+			last = time.Now()
+
+			for _, url := range n.urls {
+				if err := n.post(ctx, url, change); err != nil {
+					log.Printf("[webhook] Unable to notify %s: %v", url, err)
+				}
+			}
+		}
+	}
+}
+
+// duplicate is a method that determines whether a Change was notified within the window
+// The caller must hold the Notifier's lock
+func (n *Notifier) duplicate(change gcp.Change) bool {
+	// Forget notifications that are outside the window
+	for kk, t := range n.sent {
+		if change.Time.Sub(t) >= n.window {
+			delete(n.sent, kk)
+		}
+	}
+
+	_, ok := n.sent[key(change)]
+	return ok
+}
+
+// key returns a value that identifies a Change's action and resource
+func key(change gcp.Change) string {
+	r := change.Resource
+	return strings.Join([]string{change.Action, r.Project, r.Service, r.Type, r.Location, r.Name}, "/")
+}
+
+// post is a method that posts a Change to a webhook
+func (n *Notifier) post(ctx context.Context, url string, change gcp.Change) error {
+	var payload any = NewPayload(change)
+	if n.format == "slack" {
+		payload = NewSlackPayload(change)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	if n.dryRun {
+		log.Printf("[webhook] (dry-run) POST %s: %s", url, body)
+		return nil
+	}
+
+	rqst, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	rqst.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(rqst)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	return nil
+}
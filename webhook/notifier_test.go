@@ -0,0 +1,196 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DazWilkin/gcp-exporter/gcp"
+)
+
+// receiver is a webhook that records the notifications it receives
+type receiver struct {
+	mu       sync.Mutex
+	payloads []Payload
+	times    []time.Time
+
+	received chan struct{}
+}
+
+func newReceiver() (*receiver, *httptest.Server) {
+	r := &receiver{
+		received: make(chan struct{}, 100),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, rqst *http.Request) {
+		payload := Payload{}
+		if err := json.NewDecoder(rqst.Body).Decode(&payload); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		r.mu.Lock()
+		r.payloads = append(r.payloads, payload)
+		r.times = append(r.times, time.Now())
+		r.mu.Unlock()
+
+		r.received <- struct{}{}
+	}))
+	return r, server
+}
+
+// wait is a method that waits for n notifications or for the timeout
+func (r *receiver) wait(t *testing.T, n int, timeout time.Duration) {
+	t.Helper()
+	deadline := time.After(timeout)
+	for i := 0; i < n; i++ {
+		select {
+		case <-r.received:
+		case <-deadline:
+			t.Fatalf("received %d notifications, want %d", i, n)
+		}
+	}
+}
+
+func newChange(action, name string, now time.Time) gcp.Change {
+	return gcp.Change{
+		Time:   now,
+		Action: action,
+		Resource: gcp.Resource{
+			Project:   "my-project",
+			Service:   "compute",
+			Type:      "instance",
+			Location:  "us-west1-c",
+			Name:      name,
+			FirstSeen: now,
+		},
+	}
+}
+
+func TestNotifierDeduplicatesAndRateLimits(t *testing.T) {
+	r, server := newReceiver()
+	defer server.Close()
+
+	const interval = 100 * time.Millisecond
+	n, err := NewNotifier([]string{server.URL}, "generic", false, interval, time.Hour, 5*time.Second, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go n.Run(ctx)
+
+	now := time.Now()
+	for _, change := range []gcp.Change{
+		newChange(gcp.Created, "a", now),
+		newChange(gcp.Created, "a", now), // duplicate
+		newChange(gcp.Created, "b", now),
+		newChange(gcp.Created, "a", now.Add(time.Minute)), // duplicate within the window
+		newChange(gcp.Deleted, "a", now),                  // different action
+	} {
+		n.Notify(change)
+	}
+
+	r.wait(t, 3, 5*time.Second)
+
+	// Any further (unexpected) notifications would be posted within the interval
+	time.Sleep(3 * interval)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.payloads) != 3 {
+		t.Fatalf("received %d notifications, want 3: %+v", len(r.payloads), r.payloads)
+	}
+
+	seen := map[string]bool{}
+	for _, p := range r.payloads {
+		k := p.Action + "/" + p.Resource
+		if seen[k] {
+			t.Errorf("received duplicate notification: %s", k)
+		}
+		seen[k] = true
+	}
+	for _, k := range []string{"created/a", "created/b", "deleted/a"} {
+		if !seen[k] {
+			t.Errorf("did not receive notification: %s", k)
+		}
+	}
+
+	// Allow for scheduling jitter between the notifier and the receiver
+	const tolerance = 10 * time.Millisecond
+	for i := 1; i < len(r.times); i++ {
+		if gap := r.times[i].Sub(r.times[i-1]); gap < interval-tolerance {
+			t.Errorf("notifications %d and %d were %v apart, want at least %v", i-1, i, gap, interval)
+		}
+	}
+}
+
+func TestNotifierDryRun(t *testing.T) {
+	r, server := newReceiver()
+	defer server.Close()
+
+	const interval = 10 * time.Millisecond
+	n, err := NewNotifier([]string{server.URL}, "slack", true, interval, time.Hour, 5*time.Second, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*interval)
+	defer cancel()
+
+	now := time.Now()
+	n.Notify(newChange(gcp.Created, "a", now))
+	n.Notify(newChange(gcp.Deleted, "b", now))
+
+	// Run returns when the context is cancelled
+	n.Run(ctx)
+
+	if len(n.queue) != 0 {
+		t.Errorf("%d notifications were not processed", len(n.queue))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.payloads) != 0 {
+		t.Errorf("received %d notifications in dry-run, want 0", len(r.payloads))
+	}
+}
+
+func TestNotifierQueueFull(t *testing.T) {
+	r, server := newReceiver()
+	defer server.Close()
+
+	const interval = 10 * time.Millisecond
+	n, err := NewNotifier([]string{server.URL}, "generic", false, interval, time.Hour, 5*time.Second, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The Notifier isn't running and so the second notification is dropped
+	now := time.Now()
+	n.Notify(newChange(gcp.Created, "a", now))
+	n.Notify(newChange(gcp.Created, "b", now))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go n.Run(ctx)
+
+	r.wait(t, 1, 5*time.Second)
+
+	// A dropped notification isn't suppressed as a duplicate
+	n.Notify(newChange(gcp.Created, "b", now))
+	r.wait(t, 1, 5*time.Second)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, want := range []string{"a", "b"} {
+		if r.payloads[i].Resource != want {
+			t.Errorf("notification %d: got %q, want %q", i, r.payloads[i].Resource, want)
+		}
+	}
+}